@@ -0,0 +1,143 @@
+package btree
+
+// appendFast 在 key 大于树中所有 key 时把它追加到最右叶子的末尾并返回 true，否则不修改树并返回 false。
+// 连续追加时复用上次缓存的最右路径（见 BTree.rightmost）：叶子还有空位就直接追加，不做任何下沉；
+// 叶子满了，或者缓存已失效，再沿最右路径下沉：每层直接取最后一个孩子，不做节点内查找，
+// 路径上的满节点（BottomUp 为溢出的节点）用 appendSplitMid 不均匀地分裂。
+func (t *BTree[K, V]) appendFast(key K, value V) bool {
+	path := t.rightmost
+	if len(path) == 0 || path[0] != t.root || t.root.owner != t.owner {
+		// 缓存是空的，或者 Clone 之后节点已不属于本树，先只读地走一遍最右路径
+		path = t.rightPath(path[:0], false)
+	}
+	leaf := path[len(path)-1]
+	if last := len(leaf.items) - 1; last >= 0 && !t.greaterThan(key, leaf.items[last].key) {
+		t.rightmost = path
+		return false
+	}
+
+	if leaf.owner == t.owner && len(leaf.items) < t.maxItems(len(path) == 1) {
+		// 路径上的节点都属于本树（只有追加写过它们），叶子没满，不需要分裂
+		t.insertItemAt(leaf, len(leaf.items), item[K, V]{key: key, value: value})
+		t.rightmost = path
+		t.refreshRight()
+		return true
+	}
+
+	t.root = t.mutable(t.root)
+	path = path[:0]
+	if t.options.Strategy == Preemptive {
+		if t.isFull(t.root) {
+			t.growAt(t.appendSplitMid(len(t.root.items)))
+			t.looseRight = true
+		}
+		n := t.root
+		for !n.isLeaf {
+			path = append(path, n)
+			last := len(n.children) - 1
+			if t.isFull(n.children[last]) {
+				t.splitChildAt(n, last, t.appendSplitMid(len(n.children[last].items)))
+				t.looseRight = true
+				last++
+			}
+			n = t.mutableChild(n, last)
+		}
+		t.insertItemAt(n, len(n.items), item[K, V]{key: key, value: value})
+		path = append(path, n)
+	} else {
+		// BottomUp：先追加，再自底向上分裂溢出的节点，分裂后最右路径换成新分出的节点，重新走一遍
+		path = t.rightPath(path, true)
+		n := path[len(path)-1]
+		t.insertItemAt(n, len(n.items), item[K, V]{key: key, value: value})
+		split := false
+		for i := len(path) - 1; i > 0 && t.overflows(path[i]); i-- {
+			parent := path[i-1]
+			t.splitChildAt(parent, len(parent.children)-1, t.appendSplitMid(len(path[i].items)))
+			split = true
+		}
+		if len(t.root.items) > t.maxItems(true) {
+			t.growAt(t.appendSplitMid(len(t.root.items)))
+			split = true
+		}
+		if split {
+			t.rightmost = path
+			t.refreshRight()
+			path = t.rightPath(path[:0], false)
+			t.looseRight = true
+		}
+	}
+
+	t.rightmost = path
+	t.refreshRight()
+	return true
+}
+
+// rightPath 把从根到最右叶子的路径追加到 path 并返回。mutable 为 true 时沿途把节点变成本树的
+func (t *BTree[K, V]) rightPath(path []*node[K, V], mutable bool) []*node[K, V] {
+	if mutable {
+		t.root = t.mutable(t.root)
+	}
+	n := t.root
+	for {
+		path = append(path, n)
+		if n.isLeaf {
+			return path
+		}
+		last := len(n.children) - 1
+		if mutable {
+			n = t.mutableChild(n, last)
+		} else {
+			n = n.children[last]
+		}
+	}
+}
+
+// refreshRight 自底向上重新计算 rightmost 上每个节点的聚合值，不清空路径
+func (t *BTree[K, V]) refreshRight() {
+	if t.monoid == nil {
+		return
+	}
+	for i := len(t.rightmost) - 1; i >= 0; i-- {
+		t.refresh(t.rightmost[i])
+	}
+}
+
+// forgetRightmost 作废缓存的最右路径
+func (t *BTree[K, V]) forgetRightmost() {
+	clear(t.rightmost)
+	t.rightmost = t.rightmost[:0]
+}
+
+// endAppends 在追加以外的修改之前调用：作废缓存的最右路径，并补足最右路径上低于下限的节点，
+// 让放宽的不变式只在连续追加期间成立。
+func (t *BTree[K, V]) endAppends() {
+	t.forgetRightmost()
+	if t.looseRight {
+		t.settleRightEdge()
+	}
+}
+
+// settleRightEdge 自底向上补足最右路径上因 90/10 分裂而低于下限的节点：
+// 每层用 fixChild 从左兄弟借，借不够就与它合并。左兄弟是分裂时装满的一边，通常借一次就够。
+func (t *BTree[K, V]) settleRightEdge() {
+	t.looseRight = false
+	if t.root == nil || t.root.isLeaf {
+		return
+	}
+	path := t.rightPath(t.spine[:0], true)
+	path = path[:len(path)-1] // 叶子由它的父节点修复
+	for i := len(path) - 1; i >= 0; i-- {
+		// 修复后 path[i] 可能下溢，由上一层处理；合并可能回收 path[i+1]，所以逐层 refresh 而不是最后统一
+		t.fixChild(path[i], len(path[i].children)-1)
+		t.refresh(path[i])
+	}
+	clear(path[:cap(path)])
+	t.spine = path[:0]
+	t.shrinkRoot()
+}
+
+// appendSplitMid 返回追加时分裂一个有 count 个 key 的节点所用的中间位置：
+// 左边保留约 90% 的 key 并至少 degree-1 个，右边（新的最右节点）至少留 1 个
+func (t *BTree[K, V]) appendSplitMid(count int) int {
+	return max(t.options.Degree-1, min(count*9/10, count-2))
+}
@@ -0,0 +1,240 @@
+package btree
+
+import (
+	"fmt"
+	"maps"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func appendOptions(degree int, s Strategy) Options[int] {
+	opts := OptionsWithDegree(degree, intLess)
+	opts.Strategy = s
+	opts.FastAppend = true
+	return opts
+}
+
+func TestFastAppendFillsNodes(t *testing.T) {
+	for _, s := range []Strategy{Preemptive, BottomUp} {
+		for _, degree := range []int{4, 32} {
+			tree := NewWithOptions[int, int](appendOptions(degree, s))
+			plain := buildTreeWithStrategy(degree, s)
+			for i := 0; i < 20000; i++ {
+				tree.Set(i, i)
+				plain.Set(i, i)
+			}
+			assertVerify(t, tree)
+			if tree.Len() != 20000 {
+				t.Fatalf("strategy %d, degree %d: Len() = %d", s, degree, tree.Len())
+			}
+			// degree 很小时 90/10 分裂也只能留下约 (2d-3)/(2d-1) 的 key，所以和默认分裂比较而不是取固定阈值
+			fast, slow := tree.Stats(), plain.Stats()
+			if fast.FillFactor < slow.FillFactor+0.2 || fast.Nodes >= slow.Nodes {
+				t.Fatalf("strategy %d, degree %d: fill %.2f with %d nodes, without FastAppend %.2f with %d nodes",
+					s, degree, fast.FillFactor, fast.Nodes, slow.FillFactor, slow.Nodes)
+			}
+		}
+	}
+}
+
+func TestFastAppendMixedAgainstMap(t *testing.T) {
+	for _, s := range []Strategy{Preemptive, BottomUp} {
+		for _, degree := range []int{2, 3, 5} {
+			tree := NewWithMonoid[int, int](appendOptions(degree, s), sumMonoid)
+			model := map[int]int{}
+			rng := rand.New(rand.NewSource(int64(degree)))
+			next := 0
+			for i := 0; i < 6000; i++ {
+				switch r := rng.Intn(10); {
+				case r < 6: // 追加
+					next += 1 + rng.Intn(3)
+					tree.Set(next, i)
+					model[next] = i
+				case r < 8:
+					k := rng.Intn(next + 1)
+					tree.Set(k, i)
+					model[k] = i
+				default:
+					k := rng.Intn(next + 1)
+					if k%2 == 0 {
+						k = next // 删除最右路径上的 key
+					}
+					_, deleted := tree.Delete(k)
+					if _, ok := model[k]; ok != deleted {
+						t.Fatalf("strategy %d, degree %d: Delete(%d) = %v, want %v", s, degree, k, deleted, ok)
+					}
+					delete(model, k)
+				}
+				if i%100 == 0 {
+					assertVerify(t, tree)
+				}
+			}
+			assertVerify(t, tree)
+			assertKeys(t, tree, slices.Sorted(maps.Keys(model)))
+		}
+	}
+}
+
+func TestFastAppendAfterClone(t *testing.T) {
+	tree := NewWithOptions[int, int](appendOptions(3, Preemptive))
+	for i := 0; i < 100; i++ {
+		tree.Set(i, i)
+	}
+	clone := tree.Clone()
+	for i := 100; i < 200; i++ {
+		clone.Set(i, i)
+	}
+	assertVerify(t, tree)
+	assertVerify(t, clone)
+	if tree.Len() != 100 || clone.Len() != 200 {
+		t.Fatalf("Len() = %d and %d, want 100 and 200", tree.Len(), clone.Len())
+	}
+	if _, ok := tree.Get(150); ok {
+		t.Fatalf("append to the clone is visible in the original")
+	}
+}
+
+// 连续追加复用缓存的最右路径；其他修改作废缓存，并把最右路径补足到正常的下限
+func TestFastAppendSpineCache(t *testing.T) {
+	for _, s := range []Strategy{Preemptive, BottomUp} {
+		for _, degree := range []int{2, 3, 8} {
+			tree := NewWithMonoid[int, int](appendOptions(degree, s), sumMonoid)
+			for i := 0; i < 5000; i++ {
+				tree.Set(i, i)
+			}
+			if len(tree.rightmost) == 0 || tree.rightmost[0] != tree.root || !tree.looseRight {
+				t.Fatalf("strategy %d, degree %d: appends left no cached spine", s, degree)
+			}
+			assertVerify(t, tree)
+
+			// 追加之外的修改结束追加：缓存作废，不变式恢复到不放宽的版本
+			tree.Set(-1, -1)
+			if len(tree.rightmost) != 0 || tree.looseRight {
+				t.Fatalf("strategy %d, degree %d: Set of a small key kept the spine", s, degree)
+			}
+			assertVerify(t, tree)
+
+			for i := 5000; i < 6000; i++ {
+				tree.Set(i, i)
+			}
+			tree.Delete(2500)
+			if tree.looseRight {
+				t.Fatalf("strategy %d, degree %d: Delete left a loose right edge", s, degree)
+			}
+			assertVerify(t, tree)
+			if tree.Len() != 6000 {
+				t.Fatalf("strategy %d, degree %d: Len() = %d, want 6000", s, degree, tree.Len())
+			}
+		}
+	}
+}
+
+// Clear 回收的节点可能被重新用作根，缓存的路径不能再被使用
+func TestFastAppendAfterClearWithFreeList(t *testing.T) {
+	tree := NewWithFreeList[int, int](appendOptions(3, Preemptive), NewFreeList[int, int](64))
+	for round := 0; round < 3; round++ {
+		for i := 0; i < 1000; i++ {
+			tree.Set(i, i)
+		}
+		assertVerify(t, tree)
+		if tree.Len() != 1000 {
+			t.Fatalf("round %d: Len() = %d, want 1000", round, tree.Len())
+		}
+		tree.Clear()
+	}
+}
+
+// DeleteIf 删除过半时重建整棵树，重建不能再做 90/10 分裂，否则最右路径下溢且不会再被补足
+func TestDeleteIfAfterAppends(t *testing.T) {
+	for _, s := range []Strategy{Preemptive, BottomUp} {
+		tree := NewWithOptions[int, int](appendOptions(4, s))
+		for i := 0; i < 22; i++ {
+			tree.Set(i, i)
+		}
+		tree.DeleteIf(func(k, _ int) bool { return k%3 != 0 })
+		assertVerify(t, tree)
+		assertKeys(t, tree, []int{0, 3, 6, 9, 12, 15, 18, 21})
+
+		// Compact 清理过半的墓碑时走同一条重建路径
+		opts := appendOptions(4, s)
+		opts.LazyDelete = true
+		lazy := NewWithOptions[int, int](opts)
+		for i := 0; i < 22; i++ {
+			lazy.Set(i, i)
+		}
+		for i := 0; i < 22; i++ {
+			if i%3 != 0 {
+				lazy.Delete(i)
+			}
+		}
+		lazy.Compact()
+		assertVerify(t, lazy)
+		assertKeys(t, lazy, []int{0, 3, 6, 9, 12, 15, 18, 21})
+
+		for _, degree := range []int{2, 3, 5} {
+			rng := rand.New(rand.NewSource(int64(degree)))
+			for round := 0; round < 50; round++ {
+				tree := NewWithOptions[int, int](appendOptions(degree, s))
+				var want []int
+				n := 1 + rng.Intn(500)
+				drop := 2 + rng.Intn(4)
+				for i := 0; i < n; i++ {
+					tree.Set(i, i)
+					if i%drop == 0 {
+						want = append(want, i)
+					}
+				}
+				if round%2 == 0 {
+					tree.DeleteIf(func(k, _ int) bool { return k%drop != 0 })
+				} else {
+					tree.RetainIf(func(k, _ int) bool { return k%drop == 0 })
+				}
+				assertVerify(t, tree)
+				assertKeys(t, tree, want)
+				// 重建之后继续追加仍然走 FastAppend
+				for i := n; i < n+100; i++ {
+					tree.Set(i, i)
+					want = append(want, i)
+				}
+				assertVerify(t, tree)
+				assertKeys(t, tree, want)
+			}
+		}
+	}
+}
+
+func TestFastAppendRejectsBStar(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("FastAppend with BStar should panic")
+		}
+	}()
+	NewWithOptions[int, int](appendOptions(3, BStar))
+}
+
+// BenchmarkSequentialInsert 顺序插入 N 个 key，比较默认的 Set 与 FastAppend，并报告装载率
+func BenchmarkSequentialInsert(b *testing.B) {
+	const n = 1 << 20
+	for _, s := range []struct {
+		name     string
+		strategy Strategy
+	}{{"Preemptive", Preemptive}, {"BottomUp", BottomUp}} {
+		for _, fast := range []bool{false, true} {
+			b.Run(fmt.Sprintf("%s/FastAppend=%v", s.name, fast), func(b *testing.B) {
+				var tree *BTree[int, int]
+				for b.Loop() {
+					opts := OptionsWithDegree(32, intLess)
+					opts.Strategy = s.strategy
+					opts.FastAppend = fast
+					tree = NewWithOptions[int, int](opts)
+					for i := 0; i < n; i++ {
+						tree.Set(i, i)
+					}
+				}
+				b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*n), "ns/insert")
+				b.ReportMetric(tree.Stats().FillFactor, "fill")
+			})
+		}
+	}
+}
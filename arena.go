@@ -0,0 +1,620 @@
+package btree
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+)
+
+// ArenaBTree 是节点存放在连续内存块（slab）中的 B-Tree。
+// 所有节点的 key、value 分别放在两个大切片中，内部节点的孩子放在第三个切片中，用 uint32 下标而不是指针引用：
+// 第 n 个节点的 key 位于 keys[itemBase(n) : itemBase(n)+count]，孩子位于它的孩子块中，叶子不占孩子块。
+// 当 K、V 都不含指针时，这些切片对 GC 来说是不需要扫描的内存，
+// 千万级元素的树也不会拉长 GC 的标记时间。
+//
+// 公开的 API 与 BTree 相同，支持 Options 的全部策略和选项，算法与 BTree 一一对应。不同之处在于：
+// 根固定是第 0 个节点，分裂根时把它的内容搬到两个新节点中，降低树高时再并回来；
+// BStar 的根比其他节点大，slab 开头为它多留出位置。
+// 被合并掉的节点放入内部的空闲链表，之后分裂时优先复用，因此没有 FreeList。
+// Clone 复制整个 slab，代价是 O(n)：下标不能像指针那样在两棵树之间共享节点。
+// DeleteIf 先收集要删除的 key 再逐个删除（超过一半时重建），不做 BTree 那样的原地压缩。
+type ArenaBTree[K any, V any] struct {
+	options Options[K]
+	monoid  *Monoid[V]
+	slots   int // 非根节点的 key 槽位数：2*degree-1，BottomUp/BStar 多留一个给分裂之前短暂的溢出
+	extra   int // 根比其他节点多出的 key 槽位，只有 BStar 不为 0
+
+	keys     []K
+	values   []V
+	dead     []bool   // LazyDelete 时与 keys 平行的墓碑标记，否则为 nil
+	aggs     []V      // NewArenaWithMonoid 创建时每个节点缓存的聚合值，否则为 nil
+	children []uint32 // 内部节点的孩子，每个内部节点占一个孩子块，见 arenaNode.kids
+	nodes    []arenaNode
+	free     []uint32 // 空闲的节点下标
+	freeKids []uint32 // 空闲的孩子块下标
+
+	size       int
+	tombstones int
+
+	path       []uint32 // 下沉时记录的路径，操作之间复用，见 BTree.spine
+	rightmost  []uint32 // FastAppend 缓存的最右路径，见 BTree.rightmost
+	looseRight bool     // 最右路径上是否可能有 90/10 分裂留下的偏空节点，见 BTree.looseRight
+}
+
+// arenaNode 是节点的元数据，节点的内容在 ArenaBTree 的 slab 中
+type arenaNode struct {
+	count  int32  // key 的个数
+	kids   uint32 // 内部节点的孩子块下标，根固定使用第 0 块
+	isLeaf bool
+}
+
+// arenaRoot 是根节点的下标：根一旦分配就不再移动，第 0 个孩子块也留给它
+const arenaRoot uint32 = 0
+
+// arenaNil 是节点和孩子块下标的上限
+const arenaNil = math.MaxUint32
+
+func NewArenaBTree[K any, V any](options Options[K]) *ArenaBTree[K, V] {
+	options = tuneDegree[K, V](options).validate()
+	t := &ArenaBTree[K, V]{
+		options: options,
+		slots:   2*options.Degree - 1,
+	}
+	if options.Strategy != Preemptive {
+		t.slots++
+	}
+	if options.Strategy == BStar {
+		// 根最多 2*minItems 个 key，分裂之前还会短暂多出一个
+		t.extra = max(0, 2*t.minItems()+1-t.slots)
+	}
+	return t
+}
+
+// NewArenaWithMonoid 与 NewWithMonoid 相同：每个节点缓存其子树中所有 value 在 m 下的聚合值，
+// 供 Aggregate 和 AscendPruned 使用。
+func NewArenaWithMonoid[K any, V any](options Options[K], m Monoid[V]) *ArenaBTree[K, V] {
+	if m.Combine == nil {
+		panic("btree: Monoid.Combine must not be nil")
+	}
+	t := NewArenaBTree[K, V](options)
+	t.monoid = &m
+	return t
+}
+
+func (t *ArenaBTree[K, V]) Len() int {
+	if t == nil {
+		return 0
+	}
+	return t.size
+}
+
+// Tombstones 返回树中尚未被 Compact 清理的墓碑个数。
+func (t *ArenaBTree[K, V]) Tombstones() int {
+	if t == nil {
+		return 0
+	}
+	return t.tombstones
+}
+
+// Clear 删除所有元素，保留已分配的 slab 供之后复用
+func (t *ArenaBTree[K, V]) Clear() {
+	if t == nil {
+		return
+	}
+	clear(t.keys)
+	clear(t.values)
+	clear(t.aggs)
+	t.keys, t.values, t.dead, t.aggs = t.keys[:0], t.values[:0], t.dead[:0], t.aggs[:0]
+	t.children, t.nodes, t.free, t.freeKids = t.children[:0], t.nodes[:0], t.free[:0], t.freeKids[:0]
+	t.size = 0
+	t.tombstones = 0
+	t.forgetRightmost()
+	t.looseRight = false
+}
+
+// Clone 返回树的一个独立副本。与 BTree.Clone 不同，它复制整个 slab，代价是 O(n)。
+func (t *ArenaBTree[K, V]) Clone() *ArenaBTree[K, V] {
+	if t == nil {
+		return nil
+	}
+	c := *t
+	c.keys = slices.Clone(t.keys)
+	c.values = slices.Clone(t.values)
+	c.dead = slices.Clone(t.dead)
+	c.aggs = slices.Clone(t.aggs)
+	c.children = slices.Clone(t.children)
+	c.nodes = slices.Clone(t.nodes)
+	c.free = slices.Clone(t.free)
+	c.freeKids = slices.Clone(t.freeKids)
+	c.path = nil // 不与原树共用底层数组
+	c.rightmost = nil
+	return &c
+}
+
+// Begin 开启一个事务，见 Txn。
+func (t *ArenaBTree[K, V]) Begin() *Txn[K, V] {
+	return begin[K, V](t, t.options.Less)
+}
+
+// Freeze 返回树当前存活元素的只读快照，之后对树的修改不会影响它
+func (t *ArenaBTree[K, V]) Freeze() *FrozenTree[K, V] {
+	if t == nil {
+		return &FrozenTree[K, V]{}
+	}
+	return freeze(t.options.Less, t.size, t.Ascend)
+}
+
+// empty 报告树中是否没有任何元素（包括墓碑）
+func (t *ArenaBTree[K, V]) empty() bool {
+	return t == nil || len(t.nodes) == 0 || t.nodes[arenaRoot].count == 0
+}
+
+// minItems 返回非根节点至少要有的 key 数，与 BTree.minItems 相同
+func (t *ArenaBTree[K, V]) minItems() int {
+	if t.options.Strategy == BStar {
+		return 2 * (2*t.options.Degree - 1) / 3
+	}
+	return t.options.Degree - 1
+}
+
+// maxItems 返回节点最多能有的 key 数，与 BTree.maxItems 相同
+func (t *ArenaBTree[K, V]) maxItems(isRoot bool) int {
+	if isRoot && t.options.Strategy == BStar {
+		return 2 * t.minItems()
+	}
+	return 2*t.options.Degree - 1
+}
+
+// 节点的内容用 slab 中的下标访问：下面几个方法返回的切片直接指向 slab，
+// 分配新节点后 slab 可能被搬走，必须重新获取
+
+// itemBase 返回节点 n 的第一个 key 在 keys/values/dead 中的下标，根排在最前面并多占 extra 个位置
+func (t *ArenaBTree[K, V]) itemBase(n uint32) int {
+	if n == arenaRoot {
+		return 0
+	}
+	return int(n)*t.slots + t.extra
+}
+
+// kidBase 返回内部节点 n 的第一个孩子在 children 中的下标
+func (t *ArenaBTree[K, V]) kidBase(n uint32) int {
+	b := t.nodes[n].kids
+	if b == 0 {
+		return 0
+	}
+	return int(b)*(t.slots+1) + t.extra
+}
+
+// kidBlocks 返回已分配的孩子块个数
+func (t *ArenaBTree[K, V]) kidBlocks() int {
+	if len(t.children) == 0 {
+		return 0
+	}
+	return (len(t.children) - t.extra) / (t.slots + 1)
+}
+
+func (t *ArenaBTree[K, V]) count(n uint32) int {
+	return int(t.nodes[n].count)
+}
+
+// keysOf 返回节点 n 的 key
+func (t *ArenaBTree[K, V]) keysOf(n uint32) []K {
+	base := t.itemBase(n)
+	return t.keys[base : base+t.count(n)]
+}
+
+// childrenOf 返回内部节点 n 的孩子，长度为 count+1
+func (t *ArenaBTree[K, V]) childrenOf(n uint32) []uint32 {
+	base := t.kidBase(n)
+	return t.children[base : base+t.count(n)+1]
+}
+
+func (t *ArenaBTree[K, V]) child(n uint32, i int) uint32 {
+	return t.children[t.kidBase(n)+i]
+}
+
+// isDead 报告 slab 中第 off 个元素是否是墓碑
+func (t *ArenaBTree[K, V]) isDead(off int) bool {
+	return t.dead != nil && t.dead[off]
+}
+
+// item 返回节点 n 的第 i 个元素以及它是否是墓碑
+func (t *ArenaBTree[K, V]) item(n uint32, i int) (K, V, bool) {
+	off := t.itemBase(n) + i
+	return t.keys[off], t.values[off], t.isDead(off)
+}
+
+func (t *ArenaBTree[K, V]) setItem(n uint32, i int, key K, value V, dead bool) {
+	off := t.itemBase(n) + i
+	t.keys[off], t.values[off] = key, value
+	if t.dead != nil {
+		t.dead[off] = dead
+	}
+}
+
+// moveItems 把 slab 中从 src 开始的 cnt 个元素连同墓碑标记复制到 dst，两段可以重叠
+func (t *ArenaBTree[K, V]) moveItems(dst, src, cnt int) {
+	copy(t.keys[dst:dst+cnt], t.keys[src:src+cnt])
+	copy(t.values[dst:dst+cnt], t.values[src:src+cnt])
+	if t.dead != nil {
+		copy(t.dead[dst:dst+cnt], t.dead[src:src+cnt])
+	}
+}
+
+// clearItems 清空 slab 中从 at 开始的 cnt 个位置，让 GC 可以回收 key/value 引用的对象
+func (t *ArenaBTree[K, V]) clearItems(at, cnt int) {
+	clear(t.keys[at : at+cnt])
+	clear(t.values[at : at+cnt])
+	if t.dead != nil {
+		clear(t.dead[at : at+cnt])
+	}
+}
+
+// shift 把节点 n 的 items[at:] 连同 children[at+1:] 整体移动 delta 个位置并调整 count：
+// delta > 0 时在 at 处腾出 delta 个元素和它们右边的孩子，由调用方填入；
+// delta < 0 时覆盖 at 之前的 -delta 个元素和它们右边的孩子。
+func (t *ArenaBTree[K, V]) shift(n uint32, at, delta int) {
+	cnt, base := t.count(n), t.itemBase(n)
+	t.moveItems(base+at+delta, base+at, cnt-at)
+	if !t.nodes[n].isLeaf {
+		kb := t.kidBase(n)
+		copy(t.children[kb+at+1+delta:kb+cnt+1+delta], t.children[kb+at+1:kb+cnt+1])
+	}
+	if delta < 0 {
+		t.clearItems(base+cnt+delta, -delta)
+	}
+	t.nodes[n].count = int32(cnt + delta)
+}
+
+// insertItemAt 把元素插入到节点 n 的第 i 个位置；内部节点第 i+1 个孩子的位置空出来，由调用方填入
+func (t *ArenaBTree[K, V]) insertItemAt(n uint32, i int, key K, value V, dead bool) {
+	t.shift(n, i, 1)
+	t.setItem(n, i, key, value, dead)
+}
+
+// removeItemAt 删除并返回节点 n 的第 i 个元素，内部节点同时去掉它右边的孩子
+func (t *ArenaBTree[K, V]) removeItemAt(n uint32, i int) (K, V, bool) {
+	key, value, dead := t.item(n, i)
+	t.shift(n, i+1, -1)
+	return key, value, dead
+}
+
+// truncate 只保留节点 n 的前 cnt 个元素
+func (t *ArenaBTree[K, V]) truncate(n uint32, cnt int) {
+	t.clearItems(t.itemBase(n)+cnt, t.count(n)-cnt)
+	t.nodes[n].count = int32(cnt)
+}
+
+// growItems 在 keys/values（以及 dead）末尾追加 cnt 个位置
+func (t *ArenaBTree[K, V]) growItems(cnt int) {
+	t.keys = slices.Grow(t.keys, cnt)[:len(t.keys)+cnt]
+	t.values = slices.Grow(t.values, cnt)[:len(t.values)+cnt]
+	if t.options.LazyDelete {
+		t.dead = slices.Grow(t.dead, cnt)[:len(t.dead)+cnt]
+	}
+	if t.monoid != nil {
+		var zero V
+		t.aggs = append(t.aggs, zero)
+	}
+}
+
+// allocRoot 为空树分配根：第 0 个节点和第 0 个孩子块，都比其他节点多留 extra 个位置
+func (t *ArenaBTree[K, V]) allocRoot() {
+	t.nodes = append(t.nodes, arenaNode{isLeaf: true})
+	t.growItems(t.slots + t.extra)
+	t.children = slices.Grow(t.children, t.slots+1+t.extra)[:t.slots+1+t.extra]
+	t.refresh(arenaRoot)
+}
+
+// newNode 分配一个空节点：优先复用空闲节点，否则在 slab 末尾追加；内部节点同时分配孩子块
+func (t *ArenaBTree[K, V]) newNode(isLeaf bool) uint32 {
+	var n uint32
+	if last := len(t.free) - 1; last >= 0 {
+		n = t.free[last]
+		t.free = t.free[:last]
+	} else {
+		if len(t.nodes) >= arenaNil {
+			panic("btree: ArenaBTree has too many nodes")
+		}
+		n = uint32(len(t.nodes))
+		t.nodes = append(t.nodes, arenaNode{})
+		t.growItems(t.slots)
+	}
+	t.nodes[n] = arenaNode{isLeaf: isLeaf}
+	if !isLeaf {
+		t.nodes[n].kids = t.newKids()
+	}
+	return n
+}
+
+// newKids 分配一个孩子块：优先复用空闲的孩子块，否则在 children 末尾追加
+func (t *ArenaBTree[K, V]) newKids() uint32 {
+	if last := len(t.freeKids) - 1; last >= 0 {
+		b := t.freeKids[last]
+		t.freeKids = t.freeKids[:last]
+		return b
+	}
+	b := t.kidBlocks()
+	if b >= arenaNil {
+		panic("btree: ArenaBTree has too many nodes")
+	}
+	t.children = slices.Grow(t.children, t.slots+1)[:len(t.children)+t.slots+1]
+	return uint32(b)
+}
+
+// freeNode 把节点 n 和它的孩子块放入空闲链表，并清空它的内容以便 GC 回收 key/value 引用的对象。
+// 根不会被释放。
+func (t *ArenaBTree[K, V]) freeNode(n uint32) {
+	t.truncate(n, 0)
+	if !t.nodes[n].isLeaf {
+		t.freeKids = append(t.freeKids, t.nodes[n].kids)
+	}
+	if t.aggs != nil {
+		var zero V
+		t.aggs[n] = zero
+	}
+	t.nodes[n] = arenaNode{}
+	t.free = append(t.free, n)
+}
+
+// findIndex 在节点 n 中查找 key，与 BTree.findIndex 相同：小节点线性扫描，大节点二分查找
+func (t *ArenaBTree[K, V]) findIndex(n uint32, key K) (int, bool) {
+	keys := t.keysOf(n)
+	if threshold := t.options.BinarySearchThreshold; threshold > 0 && len(keys) >= threshold {
+		return slices.BinarySearchFunc(keys, key, t.options.Less)
+	}
+	for i, k := range keys {
+		if c := t.options.Less(k, key); c >= 0 {
+			return i, c == 0
+		}
+	}
+	return len(keys), false
+}
+
+// findIndexHint 与 BTree.findIndexHint 相同：先尝试 hint 在第 depth 层记录的位置，并把结果写回 hint
+func (t *ArenaBTree[K, V]) findIndexHint(n uint32, key K, hint *PathHint, depth int) (int, bool) {
+	if hint == nil || depth >= maxHintDepth {
+		return t.findIndex(n, key)
+	}
+	var i int
+	var found bool
+	if hint.used&(1<<depth) == 0 {
+		i, found = t.findIndex(n, key)
+	} else {
+		i, found = t.tryHint(n, key, int(hint.path[depth]))
+	}
+	hint.path[depth] = int32(i)
+	hint.used |= 1 << depth
+	return i, found
+}
+
+// tryHint 检查 key 是否落在 keys[h-1] 与 keys[h+1] 之间，是则直接返回位置，否则回退到 findIndex
+func (t *ArenaBTree[K, V]) tryHint(n uint32, key K, h int) (int, bool) {
+	keys := t.keysOf(n)
+	h = min(h, len(keys))
+	if h > 0 {
+		switch c := t.options.Less(keys[h-1], key); {
+		case c == 0:
+			return h - 1, true
+		case c > 0:
+			return t.findIndex(n, key)
+		}
+	}
+	for end := min(h+2, len(keys)); h < end; h++ {
+		if c := t.options.Less(keys[h], key); c >= 0 {
+			return h, c == 0
+		}
+	}
+	if h < len(keys) {
+		return t.findIndex(n, key)
+	}
+	return h, false
+}
+
+// Get
+func (t *ArenaBTree[K, V]) Get(key K) (V, bool) {
+	return t.GetHint(key, nil)
+}
+
+// GetHint 与 Get 相同，但用 hint 加速每一层的查找，见 BTree.GetHint。
+func (t *ArenaBTree[K, V]) GetHint(key K, hint *PathHint) (V, bool) {
+	var zero V
+	if t.empty() {
+		return zero, false
+	}
+	n := arenaRoot
+	for depth := 0; ; depth++ {
+		i, found := t.findIndexHint(n, key, hint, depth)
+		if found {
+			off := t.itemBase(n) + i
+			if t.isDead(off) {
+				return zero, false
+			}
+			return t.values[off], true
+		}
+		if t.nodes[n].isLeaf {
+			return zero, false
+		}
+		n = t.child(n, i)
+	}
+}
+
+// Stats 遍历整棵树并返回其形状信息，NodeBytes 按 BTree 节点的布局估算。
+func (t *ArenaBTree[K, V]) Stats() Stats {
+	var s Stats
+	if t == nil {
+		return s
+	}
+	s.Degree = t.options.Degree
+	s.NodeBytes = fullNodeBytes[K, V](t.options.Degree, t.options.KeyBytes)
+	if t.empty() {
+		return s
+	}
+	for n := arenaRoot; ; n = t.child(n, 0) {
+		s.Height++
+		if t.nodes[n].isLeaf {
+			break
+		}
+	}
+	t.collectStats(arenaRoot, &s)
+	s.FillFactor = float64(s.Items) / float64(s.Nodes*(2*t.options.Degree-1))
+	return s
+}
+
+func (t *ArenaBTree[K, V]) collectStats(n uint32, s *Stats) {
+	s.Nodes++
+	s.Items += t.count(n)
+	if t.nodes[n].isLeaf {
+		s.Leaves++
+		return
+	}
+	for _, child := range t.childrenOf(n) {
+		t.collectStats(child, s)
+	}
+}
+
+// Verify 检查 B-Tree 不变式、size 和 tombstones 计数、缓存的聚合值，
+// 以及每个已分配的节点和孩子块要么在树中、要么在空闲链表中
+func (t *ArenaBTree[K, V]) Verify() error {
+	if t == nil || len(t.nodes) == 0 {
+		if t != nil && t.size+t.tombstones != 0 {
+			return fmt.Errorf("btree: empty tree has size %d and %d tombstones", t.size, t.tombstones)
+		}
+		return nil
+	}
+	v := arenaVerifier{
+		seen:      make([]bool, len(t.nodes)),
+		seenKids:  make([]bool, t.kidBlocks()),
+		leafDepth: -1,
+	}
+	v.seenKids[0] = true // 根固定使用第 0 块，即使它是叶子
+	if err := t.verifyNode(arenaRoot, nil, nil, 0, &v); err != nil {
+		return err
+	}
+	if v.live != t.size {
+		return fmt.Errorf("btree: size is %d but nodes hold %d live items", t.size, v.live)
+	}
+	if v.dead != t.tombstones {
+		return fmt.Errorf("btree: tombstones is %d but nodes hold %d tombstones", t.tombstones, v.dead)
+	}
+	for _, n := range t.free {
+		if int(n) >= len(v.seen) || v.seen[n] {
+			return fmt.Errorf("btree: free node %d is invalid or still in the tree", n)
+		}
+		v.seen[n] = true
+	}
+	for n, ok := range v.seen {
+		if !ok {
+			return fmt.Errorf("btree: node %d is neither in the tree nor free", n)
+		}
+	}
+	for _, b := range t.freeKids {
+		if int(b) >= len(v.seenKids) || v.seenKids[b] {
+			return fmt.Errorf("btree: free child block %d is invalid or still in use", b)
+		}
+		v.seenKids[b] = true
+	}
+	for b, ok := range v.seenKids {
+		if !ok {
+			return fmt.Errorf("btree: child block %d is neither in use nor free", b)
+		}
+	}
+	return nil
+}
+
+// arenaVerifier 收集 Verify 遍历过程中的状态
+type arenaVerifier struct {
+	seen, seenKids []bool
+	leafDepth      int
+	live, dead     int
+}
+
+func (t *ArenaBTree[K, V]) verifyNode(n uint32, minKey, maxKey *K, depth int, v *arenaVerifier) error {
+	if int(n) >= len(t.nodes) || v.seen[n] {
+		return fmt.Errorf("btree: invalid or repeated node %d at depth %d", n, depth)
+	}
+	v.seen[n] = true
+
+	keyCount := t.count(n)
+	if n == arenaRoot {
+		if maxItems := t.maxItems(true); keyCount > maxItems {
+			return fmt.Errorf("btree: root has %d keys, max allowed %d", keyCount, maxItems)
+		}
+		if keyCount == 0 && !t.nodes[n].isLeaf {
+			return fmt.Errorf("btree: internal root has no keys")
+		}
+	} else {
+		minItems, maxItems := t.minItems(), t.maxItems(false)
+		if t.looseRight && maxKey == nil {
+			// 最右路径上的节点，连续追加时的 90/10 分裂让它可以很空
+			minItems = 1
+		}
+		if keyCount < minItems || keyCount > maxItems {
+			return fmt.Errorf("btree: non-root node at depth %d has %d keys, expect in [%d,%d]", depth, keyCount, minItems, maxItems)
+		}
+	}
+
+	base := t.itemBase(n)
+	keys := t.keysOf(n)
+	for i, key := range keys {
+		if t.isDead(base + i) {
+			v.dead++
+		} else {
+			v.live++
+		}
+		if minKey != nil && t.options.Less(key, *minKey) <= 0 {
+			return fmt.Errorf("btree: node at depth %d has key %v <= minKey %v", depth, key, *minKey)
+		}
+		if maxKey != nil && t.options.Less(key, *maxKey) >= 0 {
+			return fmt.Errorf("btree: node at depth %d has key %v >= maxKey %v", depth, key, *maxKey)
+		}
+		if i > 0 && t.options.Less(keys[i-1], key) >= 0 {
+			return fmt.Errorf("btree: node at depth %d has unordered keys: %v >= %v", depth, keys[i-1], key)
+		}
+	}
+
+	if t.nodes[n].isLeaf {
+		if v.leafDepth == -1 {
+			v.leafDepth = depth
+		} else if v.leafDepth != depth {
+			return fmt.Errorf("btree: leaf nodes have different depths: %d and %d", v.leafDepth, depth)
+		}
+		return t.verifyAggregate(n, depth)
+	}
+
+	if b := t.nodes[n].kids; n != arenaRoot {
+		if b == 0 || int(b) >= len(v.seenKids) || v.seenKids[b] {
+			return fmt.Errorf("btree: node at depth %d has invalid or shared child block %d", depth, b)
+		}
+		v.seenKids[b] = true
+	}
+	for i, child := range t.childrenOf(n) {
+		childMin, childMax := minKey, maxKey
+		if i > 0 {
+			childMin = &keys[i-1]
+		}
+		if i < keyCount {
+			childMax = &keys[i]
+		}
+		if err := t.verifyNode(child, childMin, childMax, depth+1, v); err != nil {
+			return err
+		}
+	}
+	return t.verifyAggregate(n, depth)
+}
+
+// verifyAggregate 检查节点 n 缓存的聚合值，孩子已经检查过
+func (t *ArenaBTree[K, V]) verifyAggregate(n uint32, depth int) error {
+	if t.monoid == nil {
+		return nil
+	}
+	equal := t.monoid.Equal
+	if equal == nil {
+		equal = func(a, b V) bool { return reflect.DeepEqual(a, b) }
+	}
+	if want := t.combineNode(n); !equal(t.aggs[n], want) {
+		return fmt.Errorf("btree: node at depth %d caches aggregate %v, want %v", depth, t.aggs[n], want)
+	}
+	return nil
+}
@@ -0,0 +1,441 @@
+package btree
+
+// Delete 删除 key 并返回旧值；LazyDelete 时只把它标记为墓碑
+func (t *ArenaBTree[K, V]) Delete(key K) (old V, deleted bool) {
+	if t.empty() {
+		return old, false
+	}
+	if t.options.LazyDelete {
+		return t.markDeleted(key)
+	}
+	old, deleted = t.remove(key)
+	if deleted {
+		t.size--
+	}
+	return old, deleted
+}
+
+// remove 从树中物理删除 key，不维护 size 和 tombstones，由调用方负责计数。
+// 根固定是第 0 个节点，降低树高由 absorbChildren 在合并时完成，不需要 shrinkRoot。
+func (t *ArenaBTree[K, V]) remove(key K) (old V, deleted bool) {
+	t.endAppends()
+	if t.options.Strategy != Preemptive {
+		return t.deleteBottomUp(arenaRoot, key)
+	}
+	return t.deleteTopDown(key)
+}
+
+// deleteTopDown 与 BTree.deleteFromNode 相同：下沉前保证要进入的孩子至少有 degree 个 key，
+// 经过的节点记录在 path 中，结束后自底向上重新计算聚合值。
+func (t *ArenaBTree[K, V]) deleteTopDown(key K) (old V, deleted bool) {
+	degree := t.options.Degree
+	path := t.path[:0]
+	n := arenaRoot
+	for {
+		path = append(path, n)
+		i, found := t.findIndex(n, key)
+		if t.nodes[n].isLeaf {
+			if found {
+				_, old, _ = t.removeItemAt(n, i)
+				deleted = true
+			}
+			break
+		}
+		if !found {
+			n = t.prepareChild(n, i)
+			continue
+		}
+
+		// key 在内部节点：孩子够多时用前驱或后继顶替它，否则合并后 key 下沉到合并的节点中继续删除
+		left, right := t.child(n, i), t.child(n, i+1)
+		if t.count(left) < degree && t.count(right) < degree {
+			n = t.mergeChildren(n, i)
+			continue
+		}
+		_, old, _ = t.item(n, i)
+		var k K
+		var v V
+		var dead bool
+		if t.count(left) >= degree {
+			k, v, dead, path = t.deleteMax(left, path)
+		} else {
+			k, v, dead, path = t.deleteMin(right, path)
+		}
+		t.setItem(n, i, k, v, dead)
+		deleted = true
+		break
+	}
+	t.path = t.refreshPath(path)
+	return old, deleted
+}
+
+// deleteMax 删除并返回以 n 为根的子树中的最大元素，以及它是否是墓碑；n 必须已经至少有 degree 个 key。
+// 经过的节点追加到 path 中并返回。
+func (t *ArenaBTree[K, V]) deleteMax(n uint32, path []uint32) (K, V, bool, []uint32) {
+	for !t.nodes[n].isLeaf {
+		path = append(path, n)
+		n = t.prepareChild(n, t.count(n))
+	}
+	path = append(path, n)
+	k, v, dead := t.removeItemAt(n, t.count(n)-1)
+	return k, v, dead, path
+}
+
+// deleteMin 与 deleteMax 对称，沿最左路径删除并返回最小元素
+func (t *ArenaBTree[K, V]) deleteMin(n uint32, path []uint32) (K, V, bool, []uint32) {
+	for !t.nodes[n].isLeaf {
+		path = append(path, n)
+		n = t.prepareChild(n, 0)
+	}
+	path = append(path, n)
+	k, v, dead := t.removeItemAt(n, 0)
+	return k, v, dead, path
+}
+
+// prepareChild 保证 parent 的第 i 个孩子至少有 degree 个 key，返回要下沉的孩子
+func (t *ArenaBTree[K, V]) prepareChild(parent uint32, i int) uint32 {
+	degree := t.options.Degree
+	if t.count(t.child(parent, i)) >= degree {
+		return t.child(parent, i)
+	}
+	last := t.count(parent)
+	switch {
+	case i > 0 && t.count(t.child(parent, i-1)) >= degree:
+		t.borrowFromLeft(parent, i)
+	case i < last && t.count(t.child(parent, i+1)) >= degree:
+		t.borrowFromRight(parent, i)
+	case i < last:
+		return t.mergeChildren(parent, i)
+	default:
+		return t.mergeChildren(parent, i-1)
+	}
+	return t.child(parent, i)
+}
+
+// deleteBottomUp 与 BTree.deleteBottomUp 相同：下沉时不做预先补齐，孩子下溢时在回溯阶段修复
+func (t *ArenaBTree[K, V]) deleteBottomUp(n uint32, key K) (old V, deleted bool) {
+	defer t.refresh(n)
+	i, found := t.findIndex(n, key)
+	if t.nodes[n].isLeaf {
+		if !found {
+			return old, false
+		}
+		_, old, _ = t.removeItemAt(n, i)
+		return old, true
+	}
+
+	if found {
+		// key 在内部节点：用左子树中的最大 key（前驱）顶替它
+		_, old, _ = t.item(n, i)
+		k, v, dead := t.popMax(t.child(n, i))
+		t.setItem(n, i, k, v, dead)
+		t.fixChild(n, i)
+		return old, true
+	}
+
+	old, deleted = t.deleteBottomUp(t.child(n, i), key)
+	if deleted {
+		t.fixChild(n, i)
+	}
+	return old, deleted
+}
+
+// popMax 删除并返回以 n 为根的子树中的最大元素以及它是否是墓碑，回溯时修复下溢的孩子
+func (t *ArenaBTree[K, V]) popMax(n uint32) (K, V, bool) {
+	defer t.refresh(n)
+	if t.nodes[n].isLeaf {
+		return t.removeItemAt(n, t.count(n)-1)
+	}
+	last := t.count(n)
+	k, v, dead := t.popMax(t.child(n, last))
+	t.fixChild(n, last)
+	return k, v, dead
+}
+
+// fixChild 与 BTree.fixChild 相同：修复 parent 第 idx 个孩子的下溢，先尽量从兄弟借，借不够再合并
+func (t *ArenaBTree[K, V]) fixChild(parent uint32, idx int) {
+	minItems := t.minItems()
+	child := t.child(parent, idx)
+	if t.count(child) >= minItems {
+		return
+	}
+	last := t.count(parent)
+	if idx > 0 {
+		for t.count(child) < minItems && t.count(t.child(parent, idx-1)) > minItems {
+			t.borrowFromLeft(parent, idx)
+		}
+	}
+	if idx < last {
+		for t.count(child) < minItems && t.count(t.child(parent, idx+1)) > minItems {
+			t.borrowFromRight(parent, idx)
+		}
+	}
+	if t.count(child) >= minItems {
+		return
+	}
+
+	if t.options.Strategy == BStar {
+		t.mergeStar(parent, idx)
+	} else if idx < last {
+		t.mergeChildren(parent, idx)
+	} else {
+		t.mergeChildren(parent, idx-1)
+	}
+}
+
+// mergeStar 与 BTree.mergeStar 相同：与两个兄弟一起 3 并 2（装不下时 3 分 3）；
+// 只有两个孩子的根直接把它们并入根，降低树高。
+func (t *ArenaBTree[K, V]) mergeStar(parent uint32, idx int) {
+	if parent == arenaRoot && t.count(parent) == 1 {
+		t.absorbChildren()
+		return
+	}
+
+	kids := t.count(parent) + 1
+	first := min(max(idx-1, 0), kids-3)
+	total := 2 // 两个分隔 key
+	for _, n := range t.childrenOf(parent)[first : first+3] {
+		total += t.count(n)
+	}
+	to := 2
+	if total-1 > 2*t.maxItems(false) {
+		to = 3
+	}
+	t.redistribute(parent, first, 3, to)
+}
+
+// mergeChildren 把 parent 的第 idx+1 个孩子和分隔 key 并入第 idx 个孩子并释放前者，返回合并后的节点。
+// parent 是只剩一个 key 的根时两个孩子都并入根（见 absorbChildren），返回根。
+func (t *ArenaBTree[K, V]) mergeChildren(parent uint32, idx int) uint32 {
+	if parent == arenaRoot && t.count(parent) == 1 {
+		return t.absorbChildren()
+	}
+	left, right := t.child(parent, idx), t.child(parent, idx+1)
+	lc, rc := t.count(left), t.count(right)
+
+	// 中间的 key 下沉到左孩子，右孩子的 key 和孩子接在后面
+	key, value, dead := t.item(parent, idx)
+	t.nodes[left].count = int32(lc + 1 + rc)
+	t.setItem(left, lc, key, value, dead)
+	t.moveItems(t.itemBase(left)+lc+1, t.itemBase(right), rc)
+	if !t.nodes[left].isLeaf {
+		copy(t.children[t.kidBase(left)+lc+1:], t.childrenOf(right))
+	}
+	t.removeItemAt(parent, idx)
+
+	t.freeNode(right)
+	t.refresh(left)
+	return left
+}
+
+// absorbChildren 把只剩一个 key 的根的两个孩子连同这个 key 并入根并释放它们，树高减一
+func (t *ArenaBTree[K, V]) absorbChildren() uint32 {
+	left, right := t.child(arenaRoot, 0), t.child(arenaRoot, 1)
+	lc, rc := t.count(left), t.count(right)
+	isLeaf := t.nodes[left].isLeaf
+
+	t.moveItems(lc, 0, 1) // 分隔 key 移到两半之间
+	t.moveItems(0, t.itemBase(left), lc)
+	t.moveItems(lc+1, t.itemBase(right), rc)
+	if !isLeaf {
+		copy(t.children, t.childrenOf(left))
+		copy(t.children[lc+1:], t.childrenOf(right))
+	}
+	t.nodes[arenaRoot].count = int32(lc + 1 + rc)
+	t.nodes[arenaRoot].isLeaf = isLeaf
+
+	t.freeNode(left)
+	t.freeNode(right)
+	t.refresh(arenaRoot)
+	return arenaRoot
+}
+
+// borrowFromLeft 经由父节点从左兄弟借一个 key 给第 idx 个孩子
+func (t *ArenaBTree[K, V]) borrowFromLeft(parent uint32, idx int) {
+	child, left := t.child(parent, idx), t.child(parent, idx-1)
+	lc := t.count(left)
+
+	// 父节点的分隔 key 下移到 child 的最前面，左兄弟的最后一个孩子跟着移过来
+	key, value, dead := t.item(parent, idx-1)
+	t.shift(child, 0, 1)
+	t.setItem(child, 0, key, value, dead)
+	if !t.nodes[child].isLeaf {
+		kb := t.kidBase(child)
+		t.children[kb+1] = t.children[kb]
+		t.children[kb] = t.child(left, lc)
+	}
+
+	// 左兄弟的最后一个 key 上移到父节点
+	key, value, dead = t.item(left, lc-1)
+	t.truncate(left, lc-1)
+	t.setItem(parent, idx-1, key, value, dead)
+
+	t.refresh(child)
+	t.refresh(left)
+}
+
+// borrowFromRight 经由父节点从右兄弟借一个 key 给第 idx 个孩子
+func (t *ArenaBTree[K, V]) borrowFromRight(parent uint32, idx int) {
+	child, right := t.child(parent, idx), t.child(parent, idx+1)
+	cc, rc := t.count(child), t.count(right)
+
+	// 父节点的分隔 key 下移到 child 的末尾，右兄弟的第一个孩子跟着移过来
+	key, value, dead := t.item(parent, idx)
+	t.nodes[child].count++
+	t.setItem(child, cc, key, value, dead)
+	if !t.nodes[child].isLeaf {
+		t.children[t.kidBase(child)+cc+1] = t.child(right, 0)
+		kb := t.kidBase(right)
+		copy(t.children[kb:kb+rc], t.children[kb+1:kb+rc+1])
+	}
+
+	// 右兄弟的第一个 key 上移到父节点，其余的 key 左移
+	key, value, dead = t.item(right, 0)
+	base := t.itemBase(right)
+	t.moveItems(base, base+1, rc-1)
+	t.truncate(right, rc-1)
+	t.setItem(parent, idx, key, value, dead)
+
+	t.refresh(child)
+	t.refresh(right)
+}
+
+// markDeleted 是 LazyDelete 模式下的 Delete：只把元素标记为墓碑，不改变树的结构
+func (t *ArenaBTree[K, V]) markDeleted(key K) (old V, deleted bool) {
+	path := t.path[:0]
+	n := arenaRoot
+	for {
+		path = append(path, n)
+		i, found := t.findIndex(n, key)
+		if found {
+			off := t.itemBase(n) + i
+			if !t.dead[off] {
+				old = t.values[off]
+				var zero V
+				t.values[off] = zero // 墓碑不再持有 value，让 GC 可以回收
+				t.dead[off] = true
+				t.size--
+				t.tombstones++
+				deleted = true
+			}
+			break
+		}
+		if t.nodes[n].isLeaf {
+			break
+		}
+		n = t.child(n, i)
+	}
+	t.path = t.refreshPath(path)
+
+	if th := t.options.CompactThreshold; deleted && th > 0 && float64(t.tombstones) > th*float64(t.size+t.tombstones) {
+		t.Compact()
+	}
+	return old, deleted
+}
+
+// Compact 物理删除所有墓碑并重新平衡，返回删除的墓碑个数
+func (t *ArenaBTree[K, V]) Compact() int {
+	if t == nil || t.tombstones == 0 {
+		return 0
+	}
+	n := t.tombstones
+	t.removeIf(func(_ K, _ V, dead bool) bool {
+		return dead
+	})
+	return n
+}
+
+// DeleteIf 删除所有满足 pred 的元素，返回删除的个数。
+// pred 对每个元素恰好调用一次，调用期间不能修改树。
+// 先收集命中的 key 再逐个删除；删除的元素超过一半时直接用剩余元素重建整棵树。
+func (t *ArenaBTree[K, V]) DeleteIf(pred func(k K, v V) bool) int {
+	if t.empty() {
+		return 0
+	}
+	// 墓碑对调用方不可见，不交给 pred，但可以顺带物理删除
+	return t.removeIf(func(k K, v V, dead bool) bool {
+		return dead || pred(k, v)
+	})
+}
+
+// RetainIf 只保留满足 pred 的元素，返回删除的个数。
+func (t *ArenaBTree[K, V]) RetainIf(pred func(k K, v V) bool) int {
+	return t.DeleteIf(func(k K, v V) bool {
+		return !pred(k, v)
+	})
+}
+
+// removeIf 物理删除所有满足 match 的元素，返回其中存活元素的个数。
+// match 的第三个参数表示元素是否是墓碑。
+func (t *ArenaBTree[K, V]) removeIf(match func(k K, v V, dead bool) bool) int {
+	if t.empty() {
+		return 0
+	}
+	var doomed []K // 命中的 key，按升序收集
+	live, dead := 0, 0
+	t.scan(arenaRoot, func(off int) {
+		isDead := t.isDead(off)
+		if !match(t.keys[off], t.values[off], isDead) {
+			return
+		}
+		doomed = append(doomed, t.keys[off])
+		if isDead {
+			dead++
+		} else {
+			live++
+		}
+	})
+	if len(doomed) == 0 {
+		return 0
+	}
+
+	t.endAppends()
+	if len(doomed)*2 > t.size+t.tombstones {
+		t.rebuildWithout(doomed)
+		return live
+	}
+	for _, k := range doomed {
+		t.remove(k)
+	}
+	t.size -= live
+	t.tombstones -= dead
+	return live
+}
+
+// scan 按升序对以 n 为根的子树中的每个元素（包括墓碑）调用 fn，参数是元素在 slab 中的下标。
+// fn 不能修改树。
+func (t *ArenaBTree[K, V]) scan(n uint32, fn func(off int)) {
+	base, cnt, isLeaf := t.itemBase(n), t.count(n), t.nodes[n].isLeaf
+	for i := 0; i < cnt; i++ {
+		if !isLeaf {
+			t.scan(t.child(n, i), fn)
+		}
+		fn(base + i)
+	}
+	if !isLeaf {
+		t.scan(t.child(n, cnt), fn)
+	}
+}
+
+// rebuildWithout 用树中剩余的元素重建整棵树，跳过 doomed 中列出的 key（升序）。
+// 剩余的元素先复制出来，再清空 slab 逐个插回去，重建后的树中不再有墓碑。
+// 重建不经过 FastAppend，原因见 BTree.rebuildWithout。
+func (t *ArenaBTree[K, V]) rebuildWithout(doomed []K) {
+	keep := t.size + t.tombstones - len(doomed)
+	keys, values := make([]K, 0, keep), make([]V, 0, keep)
+	j := 0
+	t.scan(arenaRoot, func(off int) {
+		if j < len(doomed) && t.options.Less(t.keys[off], doomed[j]) == 0 {
+			j++
+			return
+		}
+		keys = append(keys, t.keys[off])
+		values = append(values, t.values[off])
+	})
+	t.Clear()
+	t.allocRoot()
+	for i := range keys {
+		t.insert(keys[i], values[i], nil)
+	}
+	t.size = len(keys)
+}
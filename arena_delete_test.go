@@ -0,0 +1,97 @@
+package btree
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestArenaDeleteIfCallsPredOnce(t *testing.T) {
+	opts := OptionsWithDegree(2, intLess)
+	opts.LazyDelete = true
+	tree := NewArenaBTree[int, int](opts)
+	for i := 0; i < 300; i++ {
+		tree.Set(i, i)
+	}
+	for i := 0; i < 300; i += 10 {
+		tree.Delete(i)
+	}
+
+	calls := map[int]int{}
+	n := tree.DeleteIf(func(k, _ int) bool {
+		calls[k]++
+		return k%3 == 0
+	})
+	if len(calls) != 270 {
+		t.Fatalf("pred saw %d keys, want the 270 live ones", len(calls))
+	}
+	for k, c := range calls {
+		if c != 1 || k%10 == 0 {
+			t.Fatalf("pred called %d times for key %d", c, k)
+		}
+	}
+	// 0..299 中 3 的倍数有 100 个，其中 10 个已经是墓碑
+	if n != 90 || tree.Len() != 180 || tree.Tombstones() != 0 {
+		t.Fatalf("DeleteIf = %d, Len() = %d, Tombstones() = %d, want 90, 180, 0", n, tree.Len(), tree.Tombstones())
+	}
+	if err := tree.Verify(); err != nil {
+		t.Fatalf("Verify() = %v", err)
+	}
+}
+
+func TestArenaRetainIfRebuild(t *testing.T) {
+	for _, s := range []Strategy{Preemptive, BottomUp, BStar} {
+		opts := OptionsWithDegree(3, intLess)
+		opts.Strategy = s
+		tree := NewArenaBTree[int, int](opts)
+		for i := 0; i < 1000; i++ {
+			tree.Set(i, i)
+		}
+		allocated := len(tree.nodes)
+		// 删除超过一半时重建，重建复用已经分配的 slab
+		if n := tree.RetainIf(func(k, _ int) bool { return k%10 == 0 }); n != 900 {
+			t.Fatalf("strategy %d: RetainIf = %d, want 900", s, n)
+		}
+		if err := tree.Verify(); err != nil {
+			t.Fatalf("strategy %d: Verify() = %v", s, err)
+		}
+		if len(tree.nodes) > allocated {
+			t.Fatalf("strategy %d: rebuild grew the slab from %d to %d nodes", s, allocated, len(tree.nodes))
+		}
+		var want []int
+		for i := 0; i < 1000; i += 10 {
+			want = append(want, i)
+		}
+		if got := collect(len(want)+1, tree.Ascend); !slices.Equal(got, want) {
+			t.Fatalf("strategy %d: keys = %v", s, got)
+		}
+	}
+}
+
+func TestArenaCompactThreshold(t *testing.T) {
+	opts := OptionsWithDegree(2, intLess)
+	opts.LazyDelete = true
+	opts.CompactThreshold = 0.25
+	tree := NewArenaBTree[int, int](opts)
+	for i := 0; i < 100; i++ {
+		tree.Set(i, i)
+	}
+	for i := 0; i < 25; i++ {
+		tree.Delete(i)
+	}
+	if tree.Tombstones() != 25 {
+		t.Fatalf("Tombstones() = %d before crossing the threshold, want 25", tree.Tombstones())
+	}
+	tree.Delete(25)
+	if tree.Tombstones() != 0 || tree.Len() != 74 {
+		t.Fatalf("after crossing the threshold: Tombstones() = %d, Len() = %d, want 0 and 74", tree.Tombstones(), tree.Len())
+	}
+	if err := tree.Verify(); err != nil {
+		t.Fatalf("Verify() = %v", err)
+	}
+
+	// 复活墓碑相当于插入新元素
+	tree.Delete(50)
+	if old, replaced := tree.Set(50, 500); replaced || old != 0 || tree.Len() != 74 || tree.Tombstones() != 0 {
+		t.Fatalf("Set on a tombstone = (%d,%v), Len() = %d", old, replaced, tree.Len())
+	}
+}
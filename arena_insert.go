@@ -0,0 +1,395 @@
+package btree
+
+import "slices"
+
+// Set 插入或更新 key，返回旧值
+func (t *ArenaBTree[K, V]) Set(key K, value V) (old V, replaced bool) {
+	if t == nil {
+		return old, false
+	}
+	return t.set(key, value, nil)
+}
+
+// SetHint 与 Set 相同，但用 hint 加速每一层的查找，见 BTree.SetHint。
+func (t *ArenaBTree[K, V]) SetHint(key K, value V, hint *PathHint) (old V, replaced bool) {
+	if t == nil {
+		return old, false
+	}
+	return t.set(key, value, hint)
+}
+
+// set 实现 Set 和 SetHint，hint 可以为 nil
+func (t *ArenaBTree[K, V]) set(key K, value V, hint *PathHint) (old V, replaced bool) {
+	if len(t.nodes) == 0 {
+		t.allocRoot()
+	}
+	if t.options.FastAppend {
+		if t.appendFast(key, value) {
+			t.size++
+			return old, false
+		}
+		t.endAppends()
+	}
+	old, replaced = t.insert(key, value, hint)
+	if !replaced {
+		t.size++
+	}
+	return old, replaced
+}
+
+// insert 按 Strategy 把 key 插入树中，不经过 FastAppend，也不维护 size
+func (t *ArenaBTree[K, V]) insert(key K, value V, hint *PathHint) (old V, replaced bool) {
+	if t.options.Strategy == Preemptive {
+		// 如果根满了，增长树高
+		if t.count(arenaRoot) == t.maxItems(true) {
+			t.growRoot(t.options.Degree - 1)
+		}
+		return t.insertNonFull(key, value, hint)
+	}
+	old, replaced = t.insertBottomUp(arenaRoot, key, value, hint, 0)
+	// 溢出一路传播到根时，分裂根并增长树高
+	if c := t.count(arenaRoot); c > t.maxItems(true) {
+		if t.options.Strategy == BStar {
+			t.growRoot(c / 2)
+		} else {
+			t.growRoot(t.options.Degree - 1)
+		}
+	}
+	return old, replaced
+}
+
+// insertNonFull 从不满的根开始逐层下沉，下沉前先分裂满的孩子，与 BTree.insertNonFull 相同
+func (t *ArenaBTree[K, V]) insertNonFull(key K, value V, hint *PathHint) (old V, replaced bool) {
+	path := t.path[:0]
+	n := arenaRoot
+	for depth := 0; ; depth++ {
+		path = append(path, n)
+		i, found := t.findIndexHint(n, key, hint, depth)
+		if found {
+			old, replaced = t.overwrite(n, i, value)
+			break
+		}
+		if t.nodes[n].isLeaf {
+			t.insertItemAt(n, i, key, value, false)
+			break
+		}
+		if t.count(t.child(n, i)) == t.maxItems(false) {
+			t.splitChild(n, i, t.options.Degree-1)
+			// splitChild 之后 n 的第 i 个 key 是从孩子上浮的中间 key，它可能恰好就是要写入的 key
+			c := t.options.Less(key, t.keysOf(n)[i])
+			if c == 0 {
+				old, replaced = t.overwrite(n, i, value)
+				break
+			}
+			if c > 0 {
+				i++
+			}
+		}
+		n = t.child(n, i)
+	}
+	t.path = t.refreshPath(path)
+	return old, replaced
+}
+
+// insertBottomUp 与 BTree.insertBottomUp 相同：下沉时不做预先分裂，孩子溢出时在回溯阶段处理
+func (t *ArenaBTree[K, V]) insertBottomUp(n uint32, key K, value V, hint *PathHint, depth int) (old V, replaced bool) {
+	defer t.refresh(n)
+	i, found := t.findIndexHint(n, key, hint, depth)
+	if found {
+		return t.overwrite(n, i, value)
+	}
+	if t.nodes[n].isLeaf {
+		t.insertItemAt(n, i, key, value, false)
+		return old, false
+	}
+
+	old, replaced = t.insertBottomUp(t.child(n, i), key, value, hint, depth+1)
+	if t.count(t.child(n, i)) > t.maxItems(false) {
+		if t.options.Strategy == BStar {
+			t.relieveOverflow(n, i)
+		} else {
+			t.splitChild(n, i, t.options.Degree-1)
+		}
+	}
+	return old, replaced
+}
+
+// overwrite 用 value 覆盖节点 n 中已存在的第 i 个元素；它是墓碑时复活它并返回 replaced=false
+func (t *ArenaBTree[K, V]) overwrite(n uint32, i int, value V) (old V, replaced bool) {
+	off := t.itemBase(n) + i
+	if t.isDead(off) {
+		t.dead[off] = false
+		t.values[off] = value
+		t.tombstones--
+		return old, false
+	}
+	old = t.values[off]
+	t.values[off] = value
+	return old, true
+}
+
+// splitChild 以第 mid 个 key 为界把 parent 的第 index 个孩子分成两个节点，中间的 key 上浮到 parent
+func (t *ArenaBTree[K, V]) splitChild(parent uint32, index, mid int) {
+	child := t.child(parent, index)
+	right := t.newNode(t.nodes[child].isLeaf)
+	cnt := t.count(child)
+	t.moveItems(t.itemBase(right), t.itemBase(child)+mid+1, cnt-mid-1)
+	if !t.nodes[child].isLeaf {
+		kb := t.kidBase(child)
+		copy(t.children[t.kidBase(right):], t.children[kb+mid+1:kb+cnt+1])
+	}
+	t.nodes[right].count = int32(cnt - mid - 1)
+
+	key, value, dead := t.item(child, mid)
+	t.truncate(child, mid)
+	t.insertItemAt(parent, index, key, value, dead)
+	t.children[t.kidBase(parent)+index+1] = right
+
+	// parent 子树的内容没有变化，只有被拆开的两个节点需要重新计算聚合值
+	t.refresh(child)
+	t.refresh(right)
+}
+
+// growRoot 以根的第 mid 个 key 为界把根的两半搬到两个新节点中，根只留下中间的 key，树高加一。
+// 根固定是第 0 个节点，所以与 BTree.growAt 相反，是把内容搬出去而不是在上面加一个新根。
+func (t *ArenaBTree[K, V]) growRoot(mid int) {
+	isLeaf := t.nodes[arenaRoot].isLeaf
+	left, right := t.newNode(isLeaf), t.newNode(isLeaf)
+	cnt := t.count(arenaRoot)
+	t.moveItems(t.itemBase(left), 0, mid)
+	t.moveItems(t.itemBase(right), mid+1, cnt-mid-1)
+	if !isLeaf {
+		copy(t.children[t.kidBase(left):], t.children[:mid+1])
+		copy(t.children[t.kidBase(right):], t.children[mid+1:cnt+1])
+	}
+	t.nodes[left].count = int32(mid)
+	t.nodes[right].count = int32(cnt - mid - 1)
+
+	// 中间的 key 移到第一个位置，根的孩子块换成两个新节点
+	t.moveItems(0, mid, 1)
+	t.truncate(arenaRoot, 1)
+	t.nodes[arenaRoot].isLeaf = false
+	t.children[0], t.children[1] = left, right
+
+	t.refresh(left)
+	t.refresh(right)
+	t.refresh(arenaRoot)
+}
+
+// relieveOverflow 与 BTree.relieveOverflow 相同：先把一个 key 挪给有空位的兄弟，左右兄弟都满时 2 分 3
+func (t *ArenaBTree[K, V]) relieveOverflow(parent uint32, idx int) {
+	maxItems := t.maxItems(false)
+	last := t.count(parent)
+
+	if idx > 0 && t.count(t.child(parent, idx-1)) < maxItems {
+		t.borrowFromRight(parent, idx-1)
+		return
+	}
+	if idx < last && t.count(t.child(parent, idx+1)) < maxItems {
+		t.borrowFromLeft(parent, idx+1)
+		return
+	}
+
+	if idx < last {
+		t.redistribute(parent, idx, 2, 3)
+	} else {
+		t.redistribute(parent, idx-1, 2, 3)
+	}
+}
+
+// redistribute 与 BTree.redistribute 相同：把 parent 的第 first 到 first+from-1 个孩子
+// 连同它们之间的分隔 key 重新均分成 to 个相邻的节点。原有的节点被复用，多出来的节点新建，多余的节点释放。
+func (t *ArenaBTree[K, V]) redistribute(parent uint32, first, from, to int) {
+	nodes := slices.Clone(t.childrenOf(parent)[first : first+from])
+	isLeaf := t.nodes[nodes[0]].isLeaf
+
+	// 按顺序收集所有 key（包括分隔 key，连同墓碑标记）和孩子，然后清空原有的节点
+	var keys []K
+	var values []V
+	var dead []bool
+	var kids []uint32
+	for j, n := range nodes {
+		if j > 0 {
+			k, v, d := t.item(parent, first+j-1)
+			keys, values, dead = append(keys, k), append(values, v), append(dead, d)
+		}
+		for i := range t.count(n) {
+			k, v, d := t.item(n, i)
+			keys, values, dead = append(keys, k), append(values, v), append(dead, d)
+		}
+		if !isLeaf {
+			kids = append(kids, t.childrenOf(n)...)
+		}
+		t.truncate(n, 0)
+	}
+
+	out := make([]uint32, to)
+	var seps []int // 上浮到 parent 的分隔 key 在 keys 中的位置
+	total := len(keys) - (to - 1)
+	pos, childPos := 0, 0
+	for j := range out {
+		var n uint32
+		if j < from {
+			n = nodes[j]
+		} else {
+			n = t.newNode(isLeaf)
+		}
+		size := total / to
+		if j < total%to {
+			size++
+		}
+		base := t.itemBase(n)
+		copy(t.keys[base:], keys[pos:pos+size])
+		copy(t.values[base:], values[pos:pos+size])
+		if t.dead != nil {
+			copy(t.dead[base:], dead[pos:pos+size])
+		}
+		t.nodes[n].count = int32(size)
+		pos += size
+		if !isLeaf {
+			copy(t.children[t.kidBase(n):], kids[childPos:childPos+size+1])
+			childPos += size + 1
+		}
+		if j < to-1 {
+			seps = append(seps, pos)
+			pos++
+		}
+		t.refresh(n)
+		out[j] = n
+	}
+
+	// parent 中 from-1 个分隔 key 换成 to-1 个，from 个孩子换成 to 个
+	t.shift(parent, first+from-1, to-from)
+	for j, p := range seps {
+		t.setItem(parent, first+j, keys[p], values[p], dead[p])
+	}
+	copy(t.children[t.kidBase(parent)+first:], out)
+	if to < from {
+		for _, n := range nodes[to:] {
+			t.freeNode(n)
+		}
+	}
+}
+
+// appendFast 与 BTree.appendFast 相同：key 大于树中所有 key 时把它追加到最右叶子的末尾并返回 true，
+// 否则不修改树并返回 false。连续追加时复用缓存的最右路径，满节点用 appendSplitMid 不均匀地分裂。
+func (t *ArenaBTree[K, V]) appendFast(key K, value V) bool {
+	path := t.rightmost
+	if len(path) == 0 {
+		path = t.rightPath(path[:0])
+	}
+	leaf := path[len(path)-1]
+	if c := t.count(leaf); c > 0 && t.options.Less(key, t.keysOf(leaf)[c-1]) <= 0 {
+		t.rightmost = path
+		return false
+	}
+
+	if c := t.count(leaf); c < t.maxItems(leaf == arenaRoot) {
+		t.insertItemAt(leaf, c, key, value, false)
+		t.rightmost = path
+		t.refreshRight()
+		return true
+	}
+
+	path = path[:0]
+	if t.options.Strategy == Preemptive {
+		if c := t.count(arenaRoot); c == t.maxItems(true) {
+			t.growRoot(t.appendSplitMid(c))
+			t.looseRight = true
+		}
+		n := arenaRoot
+		for !t.nodes[n].isLeaf {
+			path = append(path, n)
+			last := t.count(n)
+			if c := t.count(t.child(n, last)); c == t.maxItems(false) {
+				t.splitChild(n, last, t.appendSplitMid(c))
+				t.looseRight = true
+				last++
+			}
+			n = t.child(n, last)
+		}
+		t.insertItemAt(n, t.count(n), key, value, false)
+		path = append(path, n)
+	} else {
+		// BottomUp：先追加，再自底向上分裂溢出的节点，分裂后最右路径换成新分出的节点，重新走一遍
+		path = t.rightPath(path)
+		n := path[len(path)-1]
+		t.insertItemAt(n, t.count(n), key, value, false)
+		split := false
+		for i := len(path) - 1; i > 0 && t.count(path[i]) > t.maxItems(false); i-- {
+			parent := path[i-1]
+			t.splitChild(parent, t.count(parent), t.appendSplitMid(t.count(path[i])))
+			split = true
+		}
+		if c := t.count(arenaRoot); c > t.maxItems(true) {
+			t.growRoot(t.appendSplitMid(c))
+			split = true
+		}
+		if split {
+			t.rightmost = path
+			t.refreshRight()
+			path = t.rightPath(path[:0])
+			t.looseRight = true
+		}
+	}
+
+	t.rightmost = path
+	t.refreshRight()
+	return true
+}
+
+// rightPath 把从根到最右叶子的路径追加到 path 并返回
+func (t *ArenaBTree[K, V]) rightPath(path []uint32) []uint32 {
+	n := arenaRoot
+	for {
+		path = append(path, n)
+		if t.nodes[n].isLeaf {
+			return path
+		}
+		n = t.child(n, t.count(n))
+	}
+}
+
+// refreshRight 自底向上重新计算 rightmost 上每个节点的聚合值，不清空路径
+func (t *ArenaBTree[K, V]) refreshRight() {
+	if t.monoid == nil {
+		return
+	}
+	for i := len(t.rightmost) - 1; i >= 0; i-- {
+		t.refresh(t.rightmost[i])
+	}
+}
+
+// forgetRightmost 作废缓存的最右路径
+func (t *ArenaBTree[K, V]) forgetRightmost() {
+	t.rightmost = t.rightmost[:0]
+}
+
+// endAppends 在追加以外的修改之前调用，见 BTree.endAppends
+func (t *ArenaBTree[K, V]) endAppends() {
+	t.forgetRightmost()
+	if t.looseRight {
+		t.settleRightEdge()
+	}
+}
+
+// settleRightEdge 自底向上补足最右路径上因 90/10 分裂而低于下限的节点，见 BTree.settleRightEdge
+func (t *ArenaBTree[K, V]) settleRightEdge() {
+	t.looseRight = false
+	if t.nodes[arenaRoot].isLeaf {
+		return
+	}
+	path := t.rightPath(t.path[:0])
+	path = path[:len(path)-1] // 叶子由它的父节点修复
+	for i := len(path) - 1; i >= 0; i-- {
+		// 合并可能释放 path[i+1]，所以逐层 refresh 而不是最后统一
+		t.fixChild(path[i], t.count(path[i]))
+		t.refresh(path[i])
+	}
+	t.path = path[:0]
+}
+
+// appendSplitMid 与 BTree.appendSplitMid 相同：左边保留约 90% 的 key 并至少 degree-1 个，右边至少留 1 个
+func (t *ArenaBTree[K, V]) appendSplitMid(count int) int {
+	return max(t.options.Degree-1, min(count*9/10, count-2))
+}
@@ -0,0 +1,81 @@
+package btree
+
+import "testing"
+
+// ArenaBTree 的插入与 BTree 算法相同，顺序写入后两棵树的形状完全一致
+func TestArenaInsertShapeMatchesBTree(t *testing.T) {
+	for _, s := range []Strategy{Preemptive, BottomUp, BStar} {
+		for _, fast := range []bool{false, true} {
+			if fast && s == BStar {
+				continue
+			}
+			for _, degree := range []int{2, 3, 8} {
+				opts := OptionsWithDegree(degree, intLess)
+				opts.Strategy = s
+				opts.FastAppend = fast
+				tree := NewWithOptions[int, int](opts)
+				arena := NewArenaBTree[int, int](opts)
+				for i := 0; i < 5000; i++ {
+					tree.Set(i, i)
+					arena.Set(i, i)
+				}
+				if err := arena.Verify(); err != nil {
+					t.Fatalf("strategy %d, fast %v, degree %d: Verify() = %v", s, fast, degree, err)
+				}
+				if got, want := arena.Stats(), tree.Stats(); got != want {
+					t.Fatalf("strategy %d, fast %v, degree %d: Stats() = %+v, want %+v", s, fast, degree, got, want)
+				}
+			}
+		}
+	}
+}
+
+// B* 的根比其他节点大，slab 开头为它多留的位置不能与第一个非根节点重叠
+func TestArenaBStarRootSlots(t *testing.T) {
+	opts := OptionsWithDegree(3, intLess)
+	opts.Strategy = BStar
+	tree := NewArenaBTree[int, int](opts)
+	if tree.extra == 0 {
+		t.Fatalf("BStar root has no extra slots")
+	}
+	for i := 0; i < 2*tree.minItems(); i++ {
+		tree.Set(i, i)
+	}
+	if !tree.nodes[arenaRoot].isLeaf || tree.count(arenaRoot) != 2*tree.minItems() {
+		t.Fatalf("root holds %d keys before growing, want %d", tree.count(arenaRoot), 2*tree.minItems())
+	}
+	for i := 2 * tree.minItems(); i < 200; i++ {
+		tree.Set(i, i)
+		if err := tree.Verify(); err != nil {
+			t.Fatalf("after Set(%d): Verify() = %v", i, err)
+		}
+	}
+	for i := 0; i < 200; i++ {
+		if v, ok := tree.Get(i); !ok || v != i {
+			t.Fatalf("Get(%d) = (%d,%v)", i, v, ok)
+		}
+	}
+}
+
+// 连续追加之后的其他修改补足最右路径，之后 Verify 按正常的下限检查
+func TestArenaFastAppendSettlesRightEdge(t *testing.T) {
+	for _, s := range []Strategy{Preemptive, BottomUp} {
+		tree := NewArenaWithMonoid[int, int](appendOptions(3, s), sumMonoid)
+		for i := 0; i < 2000; i++ {
+			tree.Set(i, i)
+		}
+		if len(tree.rightmost) == 0 || !tree.looseRight {
+			t.Fatalf("strategy %d: appends left no cached spine", s)
+		}
+		tree.Set(-1, -1)
+		if len(tree.rightmost) != 0 || tree.looseRight {
+			t.Fatalf("strategy %d: Set of a small key kept the spine", s)
+		}
+		if err := tree.Verify(); err != nil {
+			t.Fatalf("strategy %d: Verify() = %v", s, err)
+		}
+		if got, want := tree.Aggregate(-1, 2000), 1999*2000/2-1; got != want {
+			t.Fatalf("strategy %d: Aggregate = %d, want %d", s, got, want)
+		}
+	}
+}
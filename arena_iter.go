@@ -0,0 +1,220 @@
+package btree
+
+// Ascend 按升序遍历，fn 返回 false 时停止
+func (t *ArenaBTree[K, V]) Ascend(fn func(k K, v V) bool) {
+	if t.empty() {
+		return
+	}
+	t.ascend(arenaRoot, fn)
+}
+
+func (t *ArenaBTree[K, V]) ascend(n uint32, fn func(k K, v V) bool) bool {
+	base, isLeaf := t.itemBase(n), t.nodes[n].isLeaf
+	for i := 0; i < t.count(n); i++ {
+		if !isLeaf && !t.ascend(t.child(n, i), fn) {
+			return false
+		}
+		if !t.isDead(base+i) && !fn(t.keys[base+i], t.values[base+i]) {
+			return false
+		}
+	}
+	if !isLeaf {
+		return t.ascend(t.child(n, t.count(n)), fn)
+	}
+	return true
+}
+
+// AscendRange 按升序遍历 [greaterOrEqual, lessThan) 区间内的元素，fn 返回 false 时停止
+func (t *ArenaBTree[K, V]) AscendRange(greaterOrEqual, lessThan K, fn func(k K, v V) bool) {
+	if t.empty() {
+		return
+	}
+	t.ascendRange(arenaRoot, greaterOrEqual, lessThan, fn)
+}
+
+func (t *ArenaBTree[K, V]) ascendRange(n uint32, lo, hi K, fn func(k K, v V) bool) bool {
+	base, isLeaf := t.itemBase(n), t.nodes[n].isLeaf
+	i, found := t.findIndex(n, lo)
+	if !isLeaf && !found && !t.ascendRange(t.child(n, i), lo, hi, fn) {
+		return false
+	}
+	for ; i < t.count(n); i++ {
+		k := t.keys[base+i]
+		if t.options.Less(k, hi) >= 0 {
+			return false
+		}
+		if !t.isDead(base+i) && !fn(k, t.values[base+i]) {
+			return false
+		}
+		if !isLeaf && !t.ascendRange(t.child(n, i+1), lo, hi, fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// Descend 按降序遍历，fn 返回 false 时停止
+func (t *ArenaBTree[K, V]) Descend(fn func(k K, v V) bool) {
+	if t.empty() {
+		return
+	}
+	t.descend(arenaRoot, fn)
+}
+
+func (t *ArenaBTree[K, V]) descend(n uint32, fn func(k K, v V) bool) bool {
+	base, isLeaf := t.itemBase(n), t.nodes[n].isLeaf
+	for i := t.count(n) - 1; i >= 0; i-- {
+		if !isLeaf && !t.descend(t.child(n, i+1), fn) {
+			return false
+		}
+		if !t.isDead(base+i) && !fn(t.keys[base+i], t.values[base+i]) {
+			return false
+		}
+	}
+	if !isLeaf {
+		return t.descend(t.child(n, 0), fn)
+	}
+	return true
+}
+
+// Min 返回最小的 key 及其 value，树为空时 ok 为 false
+func (t *ArenaBTree[K, V]) Min() (key K, value V, ok bool) {
+	t.Ascend(func(k K, v V) bool {
+		key, value, ok = k, v, true
+		return false
+	})
+	return key, value, ok
+}
+
+// Max 返回最大的 key 及其 value，树为空时 ok 为 false
+func (t *ArenaBTree[K, V]) Max() (key K, value V, ok bool) {
+	t.Descend(func(k K, v V) bool {
+		key, value, ok = k, v, true
+		return false
+	})
+	return key, value, ok
+}
+
+// refresh 重新计算节点 n 缓存的聚合值，没有 Monoid 时什么也不做，见 BTree.refresh
+func (t *ArenaBTree[K, V]) refresh(n uint32) {
+	if t.monoid == nil {
+		return
+	}
+	t.aggs[n] = t.combineNode(n)
+}
+
+// refreshPath 自底向上 refresh 一条从上到下记录的下沉路径，返回清空的 path 以便复用
+func (t *ArenaBTree[K, V]) refreshPath(path []uint32) []uint32 {
+	for i := len(path) - 1; i >= 0; i-- {
+		t.refresh(path[i])
+	}
+	return path[:0]
+}
+
+// combineNode 按中序组合 n 的孩子聚合值与 n 自身的存活 value
+func (t *ArenaBTree[K, V]) combineNode(n uint32) V {
+	m := t.monoid
+	acc := m.Identity
+	base, isLeaf := t.itemBase(n), t.nodes[n].isLeaf
+	for i := 0; i < t.count(n); i++ {
+		if !isLeaf {
+			acc = m.Combine(acc, t.aggs[t.child(n, i)])
+		}
+		if !t.isDead(base + i) {
+			acc = m.Combine(acc, t.values[base+i])
+		}
+	}
+	if !isLeaf {
+		acc = m.Combine(acc, t.aggs[t.child(n, t.count(n))])
+	}
+	return acc
+}
+
+// Aggregate 返回 key 落在 [greaterOrEqual, lessThan) 中的所有 value 按升序组合的结果，
+// 区间为空时返回 Identity。树必须由 NewArenaWithMonoid 创建。
+func (t *ArenaBTree[K, V]) Aggregate(greaterOrEqual, lessThan K) V {
+	if t == nil || t.monoid == nil {
+		panic("btree: Aggregate requires a tree created by NewArenaWithMonoid")
+	}
+	if len(t.nodes) == 0 {
+		return t.monoid.Identity
+	}
+	return t.aggregate(arenaRoot, &greaterOrEqual, &lessThan)
+}
+
+// aggregate 与 BTree.aggregate 相同：完全落在区间内的孩子直接使用缓存的聚合值，只有两条边界路径继续下沉
+func (t *ArenaBTree[K, V]) aggregate(n uint32, lo, hi *K) V {
+	if lo == nil && hi == nil {
+		return t.aggs[n]
+	}
+
+	m := t.monoid
+	// 第 start 到 end-1 个元素都落在区间内
+	start, end := 0, t.count(n)
+	if lo != nil {
+		start, _ = t.findIndex(n, *lo)
+	}
+	if hi != nil {
+		end, _ = t.findIndex(n, *hi)
+	}
+	if end < start { // lo >= hi
+		return m.Identity
+	}
+
+	base := t.itemBase(n)
+	if t.nodes[n].isLeaf {
+		acc := m.Identity
+		for i := start; i < end; i++ {
+			if !t.isDead(base + i) {
+				acc = m.Combine(acc, t.values[base+i])
+			}
+		}
+		return acc
+	}
+
+	if start == end {
+		return t.aggregate(t.child(n, start), lo, hi)
+	}
+
+	acc := t.aggregate(t.child(n, start), lo, nil)
+	for i := start; i < end; i++ {
+		if !t.isDead(base + i) {
+			acc = m.Combine(acc, t.values[base+i])
+		}
+		if i+1 < end {
+			acc = m.Combine(acc, t.aggs[t.child(n, i+1)])
+		}
+	}
+	return m.Combine(acc, t.aggregate(t.child(n, end), nil, hi))
+}
+
+// AscendPruned 按升序遍历元素，但整棵跳过聚合值让 keep 返回 false 的子树，见 BTree.AscendPruned。
+// 树必须由 NewArenaWithMonoid 创建。
+func (t *ArenaBTree[K, V]) AscendPruned(keep func(agg V) bool, fn func(k K, v V) bool) {
+	if t == nil || t.monoid == nil {
+		panic("btree: AscendPruned requires a tree created by NewArenaWithMonoid")
+	}
+	if t.empty() {
+		return
+	}
+	t.ascendPruned(arenaRoot, keep, fn)
+}
+
+func (t *ArenaBTree[K, V]) ascendPruned(n uint32, keep func(agg V) bool, fn func(k K, v V) bool) bool {
+	if !keep(t.aggs[n]) {
+		return true
+	}
+	base, isLeaf := t.itemBase(n), t.nodes[n].isLeaf
+	for i := 0; i < t.count(n); i++ {
+		if !isLeaf && !t.ascendPruned(t.child(n, i), keep, fn) {
+			return false
+		}
+		if !t.isDead(base+i) && !fn(t.keys[base+i], t.values[base+i]) {
+			return false
+		}
+	}
+	if !isLeaf {
+		return t.ascendPruned(t.child(n, t.count(n)), keep, fn)
+	}
+	return true
+}
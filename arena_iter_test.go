@@ -0,0 +1,81 @@
+package btree
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestArenaIterSkipsTombstones(t *testing.T) {
+	opts := OptionsWithDegree(2, intLess)
+	opts.LazyDelete = true
+	tree := NewArenaBTree[int, int](opts)
+	for i := 0; i < 50; i++ {
+		tree.Set(i, i)
+	}
+	for _, k := range []int{0, 1, 25, 48, 49} {
+		tree.Delete(k)
+	}
+	if k, _, ok := tree.Min(); !ok || k != 2 {
+		t.Fatalf("Min() = %d, want 2", k)
+	}
+	if k, _, ok := tree.Max(); !ok || k != 47 {
+		t.Fatalf("Max() = %d, want 47", k)
+	}
+	if got := collect(100, func(fn func(k, v int) bool) { tree.AscendRange(23, 28, fn) }); !slices.Equal(got, []int{23, 24, 26, 27}) {
+		t.Fatalf("AscendRange(23, 28) = %v", got)
+	}
+	if got := collect(3, tree.Descend); !slices.Equal(got, []int{47, 46, 45}) {
+		t.Fatalf("Descend = %v", got)
+	}
+	if _, ok := tree.Get(25); ok {
+		t.Fatalf("Get found a tombstone")
+	}
+}
+
+func TestArenaAscendPruned(t *testing.T) {
+	tree := NewArenaWithMonoid[int, int](OptionsWithDegree(3, intLess), maxMonoid)
+	for i := 0; i < 500; i++ {
+		tree.Set(i, i%100)
+	}
+	// 只有 value >= 95 的元素可能被访问，子树的最大值小于 95 时整棵跳过
+	var got []int
+	visited := 0
+	tree.AscendPruned(func(agg int) bool { return agg >= 95 }, func(k, v int) bool {
+		visited++
+		if v >= 95 {
+			got = append(got, k)
+		}
+		return true
+	})
+	if len(got) != 25 || visited >= 500 {
+		t.Fatalf("found %d keys after visiting %d, want 25 found with pruning", len(got), visited)
+	}
+	if m := tree.Aggregate(0, 95); m != 94 {
+		t.Fatalf("Aggregate(0, 95) = %d, want 94", m)
+	}
+}
+
+// Begin 和 Freeze 与 BTree 共用实现，事务提交写回 ArenaBTree，快照不受之后的修改影响
+func TestArenaTxnAndFreeze(t *testing.T) {
+	tree := NewArenaBTree[int, int](OptionsWithDegree(2, intLess))
+	for i := 0; i < 10; i++ {
+		tree.Set(i, i)
+	}
+	frozen := tree.Freeze()
+
+	x := tree.Begin()
+	x.Set(20, 20)
+	x.Delete(3)
+	if got := collect(100, x.Ascend); !slices.Equal(got, []int{0, 1, 2, 4, 5, 6, 7, 8, 9, 20}) {
+		t.Fatalf("Txn.Ascend = %v", got)
+	}
+	if err := x.Commit(); err != nil {
+		t.Fatalf("Commit() = %v", err)
+	}
+	if _, ok := tree.Get(3); ok || tree.Len() != 10 {
+		t.Fatalf("commit not applied: Len() = %d", tree.Len())
+	}
+	if _, ok := frozen.Get(3); !ok || frozen.Len() != 10 {
+		t.Fatalf("frozen snapshot changed after commit")
+	}
+}
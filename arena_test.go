@@ -0,0 +1,259 @@
+package btree
+
+import (
+	"maps"
+	"math/rand"
+	"runtime"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestArenaRandomAgainstMap(t *testing.T) {
+	for _, degree := range []int{2, 3, 5} {
+		tree := NewArenaBTree[int, int](OptionsWithDegree(degree, intLess))
+		model := map[int]int{}
+		rng := rand.New(rand.NewSource(int64(degree)))
+		for i := 0; i < 5000; i++ {
+			k := rng.Intn(500)
+			if rng.Intn(3) == 0 {
+				old, deleted := tree.Delete(k)
+				want, ok := model[k]
+				if deleted != ok || old != want {
+					t.Fatalf("degree %d: Delete(%d) = (%d,%v), want (%d,%v)", degree, k, old, deleted, want, ok)
+				}
+				delete(model, k)
+			} else {
+				old, replaced := tree.Set(k, i)
+				want, ok := model[k]
+				if replaced != ok || old != want {
+					t.Fatalf("degree %d: Set(%d) = (%d,%v), want (%d,%v)", degree, k, old, replaced, want, ok)
+				}
+				model[k] = i
+			}
+			if i%100 == 0 {
+				if err := tree.Verify(); err != nil {
+					t.Fatalf("degree %d, step %d: Verify() = %v", degree, i, err)
+				}
+			}
+		}
+		if err := tree.Verify(); err != nil {
+			t.Fatalf("degree %d: Verify() = %v", degree, err)
+		}
+		if tree.Len() != len(model) {
+			t.Fatalf("degree %d: Len() = %d, want %d", degree, tree.Len(), len(model))
+		}
+
+		want := slices.Sorted(maps.Keys(model))
+		var got []int
+		tree.Descend(func(k, v int) bool {
+			if v != model[k] {
+				t.Fatalf("degree %d: value of %d = %d, want %d", degree, k, v, model[k])
+			}
+			got = append(got, k)
+			return true
+		})
+		slices.Reverse(got)
+		if !slices.Equal(got, want) {
+			t.Fatalf("degree %d: Descend keys mismatch", degree)
+		}
+		if k, _, ok := tree.Min(); !ok || k != want[0] {
+			t.Fatalf("degree %d: Min() = %d, want %d", degree, k, want[0])
+		}
+		if k, _, ok := tree.Max(); !ok || k != want[len(want)-1] {
+			t.Fatalf("degree %d: Max() = %d, want %d", degree, k, want[len(want)-1])
+		}
+	}
+}
+
+// arenaOptionsMatrix 返回 ArenaBTree 要覆盖的选项组合：每种策略与 LazyDelete、FastAppend、BinarySearchThreshold 的组合
+func arenaOptionsMatrix() []Options[int] {
+	var all []Options[int]
+	for _, s := range []Strategy{Preemptive, BottomUp, BStar} {
+		for _, degree := range []int{2, 3, 5} {
+			for _, lazy := range []bool{false, true} {
+				for _, fast := range []bool{false, true} {
+					if fast && s == BStar {
+						continue
+					}
+					opts := OptionsWithDegree(degree, intLess)
+					opts.Strategy = s
+					opts.LazyDelete = lazy
+					opts.FastAppend = fast
+					if degree == 5 {
+						opts.BinarySearchThreshold = 4
+					}
+					if lazy && degree == 3 {
+						opts.CompactThreshold = 0.3
+					}
+					all = append(all, opts)
+				}
+			}
+		}
+	}
+	return all
+}
+
+// pairs 按遍历顺序收集 key 和 value
+func pairs(walk func(fn func(k, v int) bool)) [][2]int {
+	var out [][2]int
+	walk(func(k, v int) bool {
+		out = append(out, [2]int{k, v})
+		return true
+	})
+	return out
+}
+
+// 对同一个随机操作序列，ArenaBTree 与相同选项的 BTree 每一步的返回值、内容、墓碑数和聚合值都相同
+func TestArenaMatchesBTree(t *testing.T) {
+	for _, opts := range arenaOptionsMatrix() {
+		tree := NewWithMonoid[int, int](opts, sumMonoid)
+		arena := NewArenaWithMonoid[int, int](opts, sumMonoid)
+		rng := rand.New(rand.NewSource(int64(opts.Degree)))
+		var hint PathHint
+		next := 0
+		check := func(step int) {
+			t.Helper()
+			if err := arena.Verify(); err != nil {
+				t.Fatalf("%+v, step %d: Verify() = %v", opts, step, err)
+			}
+			if !slices.Equal(pairs(arena.Ascend), pairs(tree.Ascend)) {
+				t.Fatalf("%+v, step %d: contents differ from BTree", opts, step)
+			}
+			if arena.Len() != tree.Len() || arena.Tombstones() != tree.Tombstones() {
+				t.Fatalf("%+v, step %d: Len/Tombstones = %d/%d, want %d/%d",
+					opts, step, arena.Len(), arena.Tombstones(), tree.Len(), tree.Tombstones())
+			}
+			lo, hi := rng.Intn(next+1), rng.Intn(next+1)
+			if got, want := arena.Aggregate(lo, hi), tree.Aggregate(lo, hi); got != want {
+				t.Fatalf("%+v, step %d: Aggregate(%d, %d) = %d, want %d", opts, step, lo, hi, got, want)
+			}
+		}
+		for step := 0; step < 3000; step++ {
+			k := rng.Intn(next + 1)
+			switch r := rng.Intn(100); {
+			case r < 35: // 追加
+				next += 1 + rng.Intn(3)
+				arena.Set(next, step)
+				tree.Set(next, step)
+			case r < 55:
+				o1, r1 := arena.SetHint(k, step, &hint)
+				o2, r2 := tree.Set(k, step)
+				if o1 != o2 || r1 != r2 {
+					t.Fatalf("%+v, step %d: SetHint(%d) = (%d,%v), want (%d,%v)", opts, step, k, o1, r1, o2, r2)
+				}
+			case r < 90:
+				o1, d1 := arena.Delete(k)
+				o2, d2 := tree.Delete(k)
+				if o1 != o2 || d1 != d2 {
+					t.Fatalf("%+v, step %d: Delete(%d) = (%d,%v), want (%d,%v)", opts, step, k, o1, d1, o2, d2)
+				}
+			case r < 93:
+				m := 2 + rng.Intn(4)
+				if n1, n2 := arena.DeleteIf(func(k, _ int) bool { return k%m == 0 }), tree.DeleteIf(func(k, _ int) bool { return k%m == 0 }); n1 != n2 {
+					t.Fatalf("%+v, step %d: DeleteIf = %d, want %d", opts, step, n1, n2)
+				}
+			case r < 95:
+				if n1, n2 := arena.RetainIf(func(k, _ int) bool { return k%7 != 0 }), tree.RetainIf(func(k, _ int) bool { return k%7 != 0 }); n1 != n2 {
+					t.Fatalf("%+v, step %d: RetainIf = %d, want %d", opts, step, n1, n2)
+				}
+			case r < 97:
+				if n1, n2 := arena.Compact(), tree.Compact(); n1 != n2 {
+					t.Fatalf("%+v, step %d: Compact = %d, want %d", opts, step, n1, n2)
+				}
+			default:
+				// 之后在副本上继续，原树不再变化
+				before := pairs(arena.Ascend)
+				old := arena
+				arena = arena.Clone()
+				arena.Set(next+1, step)
+				if !slices.Equal(pairs(old.Ascend), before) {
+					t.Fatalf("%+v, step %d: Set on the clone changed the original", opts, step)
+				}
+				tree.Set(next+1, step)
+				next++
+			}
+			if step%50 == 0 {
+				check(step)
+			}
+		}
+		check(-1)
+		if s := arena.Stats(); s.Items != arena.Len()+arena.Tombstones() || s.NodeBytes != tree.Stats().NodeBytes {
+			t.Fatalf("%+v: Stats() = %+v, want %d items", opts, s, arena.Len()+arena.Tombstones())
+		}
+	}
+}
+
+func TestArenaReusesFreedNodes(t *testing.T) {
+	tree := NewArenaBTree[int, int](OptionsWithDegree(2, intLess))
+	for i := 0; i < 1000; i++ {
+		tree.Set(i, i)
+	}
+	allocated := len(tree.nodes)
+	for i := 0; i < 1000; i++ {
+		tree.Delete(i)
+	}
+	// 根固定是第 0 个节点，树空了也不释放
+	if tree.Len() != 0 || len(tree.free) != allocated-1 || len(tree.freeKids) != tree.kidBlocks()-1 {
+		t.Fatalf("after deleting all: Len() = %d, free = %d, want 0 and %d", tree.Len(), len(tree.free), allocated-1)
+	}
+	for i := 0; i < 1000; i++ {
+		tree.Set(i, i)
+	}
+	if len(tree.nodes) != allocated {
+		t.Fatalf("nodes grew to %d after refill, want freed nodes reused (%d)", len(tree.nodes), allocated)
+	}
+	if err := tree.Verify(); err != nil {
+		t.Fatalf("Verify() = %v", err)
+	}
+
+	tree.Clear()
+	if _, _, ok := tree.Min(); ok || tree.Len() != 0 {
+		t.Fatalf("Clear() left items behind")
+	}
+	tree.Set(1, 1)
+	if v, ok := tree.Get(1); !ok || v != 1 {
+		t.Fatalf("Get(1) after Clear = (%d,%v)", v, ok)
+	}
+}
+
+// gcTree 是 GC 基准中两种实现共用的写入接口
+type gcTree interface {
+	Set(key, value int) (int, bool)
+}
+
+// benchmarkGC 建一棵有 n 个元素的树，测量树存活时一次完整 GC 的耗时和堆大小
+func benchmarkGC(b *testing.B, n int, newTree func() gcTree) {
+	tree := newTree()
+	for _, k := range rand.New(rand.NewSource(1)).Perm(n) {
+		tree.Set(k, k)
+	}
+	runtime.GC()
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	heap := ms.HeapAlloc
+
+	var total time.Duration
+	for b.Loop() {
+		start := time.Now()
+		runtime.GC()
+		total += time.Since(start)
+	}
+	runtime.KeepAlive(tree)
+	b.ReportMetric(float64(total.Nanoseconds())/float64(b.N), "gc-ns/op")
+	b.ReportMetric(float64(heap)/float64(n), "heap-bytes/item")
+}
+
+func BenchmarkArenaGC(b *testing.B) {
+	const n = 1_000_000
+	b.Run("BTree", func(b *testing.B) {
+		benchmarkGC(b, n, func() gcTree {
+			return NewWithOptions[int, int](OptionsWithDegree(32, intLess))
+		})
+	})
+	b.Run("ArenaBTree", func(b *testing.B) {
+		benchmarkGC(b, n, func() gcTree {
+			return NewArenaBTree[int, int](OptionsWithDegree(32, intLess))
+		})
+	})
+}
@@ -0,0 +1,181 @@
+package btree
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Monoid 描述 value 上的一个幺半群：Combine 满足结合律，Identity 是它的单位元。
+// 例如求和（Identity 为 0，Combine 为加法）、求最大值（Identity 为最小值，Combine 为 max）。
+// Combine 不要求满足交换律，聚合总是按 key 的升序组合。
+type Monoid[V any] struct {
+	Identity V
+	Combine  func(a, b V) V
+	// Equal 供 Verify 比较缓存的聚合值，为 nil 时使用 reflect.DeepEqual
+	Equal func(a, b V) bool
+}
+
+// NewWithMonoid 创建一棵增强树：每个节点缓存其子树中所有 value 在 m 下的聚合值，
+// Aggregate 可以在 O(log n) 时间内求出任意 key 区间的聚合。
+// Monoid 作用在 value 类型 V 上，而 Options 只以 K 为类型参数，所以单独传入。
+func NewWithMonoid[K any, V any](options Options[K], m Monoid[V]) *BTree[K, V] {
+	if m.Combine == nil {
+		panic("btree: Monoid.Combine must not be nil")
+	}
+	t := NewWithOptions[K, V](options)
+	t.monoid = &m
+	return t
+}
+
+// refresh 根据 n 的 items 和孩子的聚合值重新计算 n 缓存的聚合值，没有 Monoid 时什么也不做。
+// 所有改变子树内容的操作都要在返回前对受影响的节点调用它（自底向上）。
+func (t *BTree[K, V]) refresh(n *node[K, V]) {
+	if t.monoid == nil {
+		return
+	}
+	n.extension().agg = t.combineNode(n)
+}
+
+// agg 返回 n 缓存的聚合值，只对 NewWithMonoid 创建的树有意义
+func (n *node[K, V]) agg() V {
+	if n.ext == nil {
+		var zero V
+		return zero
+	}
+	return n.ext.agg
+}
+
+// refreshPath 自底向上 refresh 一条从上到下记录的下沉路径，清空 path 并返回它以便复用（见 BTree.spine）
+func (t *BTree[K, V]) refreshPath(path []*node[K, V]) []*node[K, V] {
+	for i := len(path) - 1; i >= 0; i-- {
+		t.refresh(path[i])
+	}
+	clear(path)
+	return path[:0]
+}
+
+// combineNode 按中序组合 n 的孩子聚合值与 n 自身的存活 value
+func (t *BTree[K, V]) combineNode(n *node[K, V]) V {
+	m := t.monoid
+	acc := m.Identity
+	for i, it := range n.items {
+		if !n.isLeaf {
+			acc = m.Combine(acc, n.children[i].agg())
+		}
+		if !n.isDead(i) {
+			acc = m.Combine(acc, it.value)
+		}
+	}
+	if !n.isLeaf {
+		acc = m.Combine(acc, n.children[len(n.items)].agg())
+	}
+	return acc
+}
+
+// Aggregate 返回 key 落在 [greaterOrEqual, lessThan) 中的所有 value 按升序组合的结果，
+// 区间为空时返回 Identity。树必须由 NewWithMonoid 创建。
+func (t *BTree[K, V]) Aggregate(greaterOrEqual, lessThan K) V {
+	if t == nil || t.monoid == nil {
+		panic("btree: Aggregate requires a tree created by NewWithMonoid")
+	}
+	if t.root == nil {
+		return t.monoid.Identity
+	}
+	return t.aggregate(t.root, &greaterOrEqual, &lessThan)
+}
+
+// aggregate 计算 n 的子树中落在 [lo, hi) 内的 value 的聚合，lo/hi 为 nil 表示该侧无界。
+// 完全落在区间内的孩子直接使用缓存的聚合值，只有两条边界路径需要继续下沉。
+func (t *BTree[K, V]) aggregate(n *node[K, V], lo, hi *K) V {
+	if lo == nil && hi == nil {
+		return n.agg()
+	}
+
+	m := t.monoid
+	// items[start:end] 都落在区间内
+	start, end := 0, len(n.items)
+	if lo != nil {
+		start, _ = t.findIndex(n, *lo)
+	}
+	if hi != nil {
+		end, _ = t.findIndex(n, *hi)
+	}
+	if end < start { // lo >= hi
+		return m.Identity
+	}
+
+	if n.isLeaf {
+		acc := m.Identity
+		for i := start; i < end; i++ {
+			if !n.isDead(i) {
+				acc = m.Combine(acc, n.items[i].value)
+			}
+		}
+		return acc
+	}
+
+	if start == end {
+		// 整个区间落在同一个孩子内
+		return t.aggregate(n.children[start], lo, hi)
+	}
+
+	// children[start] 只受左边界约束，children[end] 只受右边界约束，中间的孩子整体落在区间内
+	acc := t.aggregate(n.children[start], lo, nil)
+	for i := start; i < end; i++ {
+		if !n.isDead(i) {
+			acc = m.Combine(acc, n.items[i].value)
+		}
+		if i+1 < end {
+			acc = m.Combine(acc, n.children[i+1].agg())
+		}
+	}
+	return m.Combine(acc, t.aggregate(n.children[end], nil, hi))
+}
+
+// AscendPruned 按升序遍历元素，但整棵跳过聚合值让 keep 返回 false 的子树；fn 返回 false 时停止。
+// 用于在增强树上做剪枝搜索，例如区间树跳过最大右端点小于查询起点的子树。
+// keep 只决定子树是否下沉，子树中的每个元素仍需要 fn 自己过滤。树必须由 NewWithMonoid 创建。
+func (t *BTree[K, V]) AscendPruned(keep func(agg V) bool, fn func(k K, v V) bool) {
+	if t == nil || t.monoid == nil {
+		panic("btree: AscendPruned requires a tree created by NewWithMonoid")
+	}
+	if t.root == nil {
+		return
+	}
+	t.ascendPruned(t.root, keep, fn)
+}
+
+func (t *BTree[K, V]) ascendPruned(n *node[K, V], keep func(agg V) bool, fn func(k K, v V) bool) bool {
+	if !keep(n.agg()) {
+		return true
+	}
+	for i, it := range n.items {
+		if !n.isLeaf && !t.ascendPruned(n.children[i], keep, fn) {
+			return false
+		}
+		if !n.isDead(i) && !fn(it.key, it.value) {
+			return false
+		}
+	}
+	if !n.isLeaf {
+		return t.ascendPruned(n.children[len(n.items)], keep, fn)
+	}
+	return true
+}
+
+// verifyAggregates 自底向上检查每个节点缓存的聚合值是否正确
+func (t *BTree[K, V]) verifyAggregates(n *node[K, V], depth int) error {
+	for _, child := range n.children {
+		if err := t.verifyAggregates(child, depth+1); err != nil {
+			return err
+		}
+	}
+	equal := t.monoid.Equal
+	if equal == nil {
+		equal = func(a, b V) bool { return reflect.DeepEqual(a, b) }
+	}
+	if want := t.combineNode(n); !equal(n.agg(), want) {
+		return fmt.Errorf("btree: node at depth %d caches aggregate %v, want %v", depth, n.agg(), want)
+	}
+	return nil
+}
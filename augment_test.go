@@ -0,0 +1,224 @@
+package btree
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+	"unsafe"
+)
+
+var sumMonoid = Monoid[int]{
+	Identity: 0,
+	Combine:  func(a, b int) int { return a + b },
+}
+
+var maxMonoid = Monoid[int]{
+	Identity: math.MinInt,
+	Combine:  func(a, b int) int { return max(a, b) },
+}
+
+func bruteAggregate(m Monoid[int], model map[int]int, lo, hi int) int {
+	// Combine 满足交换律时与顺序无关，可以直接遍历 map
+	acc := m.Identity
+	for k, v := range model {
+		if k >= lo && k < hi {
+			acc = m.Combine(acc, v)
+		}
+	}
+	return acc
+}
+
+func TestAggregateSumAndMax(t *testing.T) {
+	strategies := []struct {
+		name     string
+		strategy Strategy
+		lazy     bool
+	}{
+		{"Preemptive", Preemptive, false},
+		{"BottomUp", BottomUp, false},
+		{"BStar", BStar, false},
+		{"LazyDelete", Preemptive, true},
+	}
+	monoids := []struct {
+		name   string
+		monoid Monoid[int]
+	}{
+		{"sum", sumMonoid},
+		{"max", maxMonoid},
+	}
+
+	for _, s := range strategies {
+		for _, mo := range monoids {
+			t.Run(s.name+"/"+mo.name, func(t *testing.T) {
+				opts := OptionsWithDegree(3, intLess)
+				opts.Strategy = s.strategy
+				opts.LazyDelete = s.lazy
+				tree := NewWithMonoid[int, int](opts, mo.monoid)
+				model := make(map[int]int)
+				r := rand.New(rand.NewSource(1))
+
+				for i := 0; i < 3000; i++ {
+					k := r.Intn(400)
+					switch r.Intn(10) {
+					case 0, 1, 2:
+						tree.Delete(k)
+						delete(model, k)
+					case 3:
+						if r.Intn(20) == 0 {
+							tree.DeleteIf(func(k, v int) bool { return k%7 == 0 })
+							for k := range model {
+								if k%7 == 0 {
+									delete(model, k)
+								}
+							}
+						}
+					default:
+						v := r.Intn(1000) - 500
+						tree.Set(k, v)
+						model[k] = v
+					}
+
+					if i%100 == 0 {
+						assertVerify(t, tree)
+					}
+					lo := r.Intn(450) - 25
+					hi := lo + r.Intn(200)
+					if got, want := tree.Aggregate(lo, hi), bruteAggregate(mo.monoid, model, lo, hi); got != want {
+						t.Fatalf("step %d: Aggregate(%d,%d) = %d, want %d", i, lo, hi, got, want)
+					}
+				}
+				assertVerify(t, tree)
+			})
+		}
+	}
+}
+
+func TestAggregateNonCommutative(t *testing.T) {
+	// 字符串拼接不满足交换律，聚合必须严格按 key 升序组合
+	concat := Monoid[string]{
+		Identity: "",
+		Combine:  func(a, b string) string { return a + b },
+	}
+	tree := NewWithMonoid[int, string](OptionsWithDegree(2, intLess), concat)
+	letters := "abcdefghijklmnopqrstuvwxyz"
+	for _, i := range rand.New(rand.NewSource(2)).Perm(len(letters)) {
+		tree.Set(i, letters[i:i+1])
+	}
+
+	if got := tree.Aggregate(0, 26); got != letters {
+		t.Fatalf("Aggregate(0,26) = %q, want %q", got, letters)
+	}
+	if got := tree.Aggregate(3, 9); got != "defghi" {
+		t.Fatalf("Aggregate(3,9) = %q, want %q", got, "defghi")
+	}
+	if got := tree.Aggregate(9, 3); got != "" {
+		t.Fatalf("Aggregate(9,3) = %q, want empty", got)
+	}
+}
+
+func TestAggregateOverwriteAndEmpty(t *testing.T) {
+	tree := NewWithMonoid[int, int](OptionsWithDegree(2, intLess), sumMonoid)
+	if got := tree.Aggregate(0, 100); got != 0 {
+		t.Fatalf("Aggregate on empty tree = %d, want 0", got)
+	}
+
+	for i := 0; i < 50; i++ {
+		tree.Set(i, 1)
+	}
+	tree.Set(10, 100) // 覆盖 value 同样要更新路径上的聚合值
+	if got := tree.Aggregate(0, 50); got != 149 {
+		t.Fatalf("Aggregate(0,50) = %d, want 149", got)
+	}
+	assertVerify(t, tree)
+}
+
+func TestVerify_DetectStaleAggregate(t *testing.T) {
+	tree := NewWithMonoid[int, int](OptionsWithDegree(2, intLess), sumMonoid)
+	for i := 0; i < 20; i++ {
+		tree.Set(i, i)
+	}
+	tree.root.children[0].items[0].value += 5 // 绕过 Set 修改 value，缓存失效
+
+	if err := tree.Verify(); err == nil {
+		t.Fatalf("expected Verify() to fail on a stale aggregate, got nil")
+	}
+}
+
+func TestAggregateWithoutMonoidPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("Aggregate on a plain tree should panic")
+		}
+	}()
+	buildTree(2, 1, 2, 3).Aggregate(0, 10)
+}
+
+// 聚合值放在 nodeExt 中：节点大小与 V 无关，普通树的节点不分配 ext
+func TestAggregatesOnlyInMonoidTrees(t *testing.T) {
+	if big, small := unsafe.Sizeof(node[int, [64]byte]{}), unsafe.Sizeof(node[int, int]{}); big != small {
+		t.Fatalf("node[int, [64]byte] is %d bytes, node[int, int] %d", big, small)
+	}
+
+	plain := buildTree(2)
+	augmented := NewWithMonoid[int, int](OptionsWithDegree(2, intLess), sumMonoid)
+	for i := 0; i < 50; i++ {
+		plain.Set(i, i)
+		augmented.Set(i, i)
+	}
+	clone := augmented.Clone()
+	clone.Set(50, 50) // 复制路径上的节点，聚合值随 ext 一起复制
+	assertVerify(t, clone)
+	for _, tree := range []*BTree[int, int]{plain, augmented, clone} {
+		var walk func(n *node[int, int])
+		walk = func(n *node[int, int]) {
+			if (n.ext != nil) != (tree.monoid != nil) {
+				t.Fatalf("node %v: has ext = %v, tree has a monoid = %v", nodeKeys(n), n.ext != nil, tree.monoid != nil)
+			}
+			for _, child := range n.children {
+				walk(child)
+			}
+		}
+		walk(tree.root)
+	}
+}
+
+func TestAscendPruned(t *testing.T) {
+	const N, limit = 2000, 1990
+	r := rand.New(rand.NewSource(1))
+	tree := NewWithMonoid[int, int](OptionsWithDegree(3, intLess), maxMonoid)
+	var want []int
+	for k := 0; k < N; k++ {
+		v := r.Intn(N)
+		tree.Set(k, v)
+		if v >= limit {
+			want = append(want, k)
+		}
+	}
+
+	// 只有最大值不小于 limit 的子树才会被访问
+	var got []int
+	calls := 0
+	tree.AscendPruned(func(agg int) bool { return agg >= limit }, func(k, v int) bool {
+		calls++
+		if v >= limit {
+			got = append(got, k)
+		}
+		return true
+	})
+	if !slices.Equal(got, want) {
+		t.Fatalf("AscendPruned found %v, want %v", got, want)
+	}
+	if calls >= N/2 {
+		t.Fatalf("AscendPruned visited %d of %d items, want most subtrees pruned", calls, N)
+	}
+
+	calls = 0
+	tree.AscendPruned(func(int) bool { return true }, func(k, v int) bool {
+		calls++
+		return k < 10
+	})
+	if calls != 11 {
+		t.Fatalf("AscendPruned made %d calls after fn returned false, want 11", calls)
+	}
+}
@@ -0,0 +1,69 @@
+package btree
+
+// insertBottomUp 把 key 插入以 n 为根的子树，下沉时不做预先分裂。
+// 孩子溢出时在回溯阶段分裂它，分裂上浮的 key 可能让 n 也溢出，交给 n 的父节点处理。
+// n 位于第 depth 层，hint 可以为 nil（见 SetHint）。
+func (t *BTree[K, V]) insertBottomUp(n *node[K, V], key K, value V, hint *PathHint, depth int) (old V, replaced bool) {
+	defer t.refresh(n)
+	i, found := t.findIndexHint(n, key, hint, depth)
+	if found {
+		return t.overwrite(n, i, value)
+	}
+
+	if n.isLeaf {
+		t.insertItemAt(n, i, item[K, V]{key: key, value: value})
+		return old, false
+	}
+
+	old, replaced = t.insertBottomUp(t.mutableChild(n, i), key, value, hint, depth+1)
+	if t.overflows(n.children[i]) {
+		if t.options.Strategy == BStar {
+			t.relieveOverflow(n, i)
+		} else {
+			t.splitChild(n, i)
+		}
+	}
+	return old, replaced
+}
+
+// deleteBottomUp 在以 n 为根的子树中删除 key，下沉时不做预先补齐。
+// 孩子下溢时在回溯阶段通过借位或合并修复，n 自身的下溢交给 n 的父节点处理。
+func (t *BTree[K, V]) deleteBottomUp(n *node[K, V], key K) (old V, deleted bool) {
+	defer t.refresh(n)
+	i, found := t.findIndex(n, key)
+
+	if n.isLeaf {
+		if !found {
+			return old, false
+		}
+		return t.deleteFromLeaf(n, i)
+	}
+
+	if found {
+		// key 在内部节点：用左子树中的最大 key（前驱）顶替它
+		old = n.items[i].value
+		it, dead := t.popMax(t.mutableChild(n, i))
+		n.setItem(i, it, dead)
+		t.fixChild(n, i)
+		return old, true
+	}
+
+	old, deleted = t.deleteBottomUp(t.mutableChild(n, i), key)
+	if deleted {
+		t.fixChild(n, i)
+	}
+	return old, deleted
+}
+
+// popMax 删除并返回以 n 为根的子树中的最大元素以及它是否是墓碑，回溯时修复下溢的孩子。
+func (t *BTree[K, V]) popMax(n *node[K, V]) (item[K, V], bool) {
+	defer t.refresh(n)
+	if n.isLeaf {
+		return n.removeItem(len(n.items) - 1)
+	}
+
+	last := len(n.children) - 1
+	it, dead := t.popMax(t.mutableChild(n, last))
+	t.fixChild(n, last)
+	return it, dead
+}
@@ -0,0 +1,147 @@
+package btree
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func buildTreeWithStrategy(degree int, s Strategy, keys ...int) *BTree[int, int] {
+	opts := OptionsWithDegree(degree, intLess)
+	opts.Strategy = s
+	tree := NewWithOptions[int, int](opts)
+	for _, k := range keys {
+		tree.Set(k, k)
+	}
+	return tree
+}
+
+func TestBottomUpSetGetDelete(t *testing.T) {
+	for _, degree := range []int{2, 3, 4, 16} {
+		r := rand.New(rand.NewSource(int64(degree)))
+		tree := buildTreeWithStrategy(degree, BottomUp)
+		model := make(map[int]int)
+
+		for i := 0; i < 3000; i++ {
+			k := r.Intn(500)
+			if r.Intn(3) == 0 {
+				old, deleted := tree.Delete(k)
+				want, ok := model[k]
+				if deleted != ok || old != want {
+					t.Fatalf("degree %d: Delete(%d) = (%d,%v), want (%d,%v)", degree, k, old, deleted, want, ok)
+				}
+				delete(model, k)
+			} else {
+				old, replaced := tree.Set(k, i)
+				want, ok := model[k]
+				if replaced != ok || old != want {
+					t.Fatalf("degree %d: Set(%d) = (%d,%v), want (%d,%v)", degree, k, old, replaced, want, ok)
+				}
+				model[k] = i
+			}
+			if i%100 == 0 {
+				assertVerify(t, tree)
+			}
+		}
+
+		var want []int
+		for k := range model {
+			want = append(want, k)
+		}
+		slices.Sort(want)
+		assertVerify(t, tree)
+		assertKeys(t, tree, want)
+		if tree.Len() != len(want) {
+			t.Fatalf("degree %d: Len() = %d, want %d", degree, tree.Len(), len(want))
+		}
+	}
+}
+
+func TestBottomUpDeleteAll(t *testing.T) {
+	const N = 300
+	keys := make([]int, N)
+	for i := range keys {
+		keys[i] = i
+	}
+	tree := buildTreeWithStrategy(3, BottomUp, keys...)
+
+	// 从中间往两边删，覆盖内部节点删除、借位与合并
+	for i := 0; i < N/2; i++ {
+		for _, k := range []int{N/2 + i, N/2 - 1 - i} {
+			if _, deleted := tree.Delete(k); !deleted {
+				t.Fatalf("Delete(%d) reported missing key", k)
+			}
+		}
+		assertVerify(t, tree)
+	}
+	if tree.Len() != 0 || tree.root != nil {
+		t.Fatalf("tree should be empty, got Len()=%d root=%+v", tree.Len(), tree.root)
+	}
+}
+
+// 叶子还有空位时，自底向上插入不会分裂路径上的满节点
+func TestBottomUpSkipsPreemptiveSplit(t *testing.T) {
+	build := func(s Strategy) *BTree[int, int] {
+		root := internalNode([]int{20, 40, 60}, leaf(10), leaf(30), leaf(50), leaf(70))
+		tree := treeFromRoot(root, 2)
+		tree.options.Strategy = s
+		return tree
+	}
+
+	pre := build(Preemptive)
+	pre.Set(35, 35)
+	up := build(BottomUp)
+	up.Set(35, 35)
+
+	assertVerify(t, pre)
+	assertVerify(t, up)
+	if got := pre.Stats(); got.Nodes != 7 || got.Height != 3 {
+		t.Fatalf("Preemptive stats = %+v, want 7 nodes and height 3", got)
+	}
+	if got := up.Stats(); got.Nodes != 5 || got.Height != 2 {
+		t.Fatalf("BottomUp stats = %+v, want 5 nodes and height 2", got)
+	}
+}
+
+func TestBottomUpFewerNodesThanPreemptive(t *testing.T) {
+	for _, degree := range []int{2, 3} {
+		r := rand.New(rand.NewSource(1))
+		keys := make([]int, 20000)
+		for i := range keys {
+			keys[i] = r.Int()
+		}
+
+		pre := buildTreeWithStrategy(degree, Preemptive, keys...).Stats()
+		up := buildTreeWithStrategy(degree, BottomUp, keys...).Stats()
+		t.Logf("degree %d: preemptive %+v, bottom-up %+v", degree, pre, up)
+
+		if up.Nodes >= pre.Nodes {
+			t.Fatalf("degree %d: bottom-up uses %d nodes, want fewer than preemptive %d", degree, up.Nodes, pre.Nodes)
+		}
+		if up.FillFactor <= pre.FillFactor {
+			t.Fatalf("degree %d: bottom-up fill factor %.3f, want above preemptive %.3f", degree, up.FillFactor, pre.FillFactor)
+		}
+		if up.Height > pre.Height {
+			t.Fatalf("degree %d: bottom-up height %d, want at most preemptive %d", degree, up.Height, pre.Height)
+		}
+	}
+}
+
+func TestStats(t *testing.T) {
+	var empty BTree[int, int]
+	if got := empty.Stats(); got != (Stats{}) {
+		t.Fatalf("Stats() on empty tree = %+v, want zero", got)
+	}
+	emptyTree := NewWithOptions[int, int](OptionsWithDegree(4, intLess))
+	if got := emptyTree.Stats(); got.Nodes != 0 || got.Height != 0 || got.Degree != 4 {
+		t.Fatalf("Stats() on empty tree = %+v, want no nodes and degree 4", got)
+	}
+
+	root := internalNode([]int{30}, leaf(10, 20), leaf(40))
+	tree := treeFromRoot(root, 2)
+	want := Stats{Height: 2, Nodes: 3, Leaves: 2, Items: 4, FillFactor: 4.0 / 9,
+		Degree: 2, NodeBytes: fullNodeBytes[int, int](2, 0)}
+	if got := tree.Stats(); got != want {
+		t.Fatalf("Stats() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,441 @@
+package btree
+
+import "fmt"
+
+// BPlusTree 是与 BTree 接口一致的 B+ 树：
+// value 只存放在叶子中，内部节点只保存分隔 key 的副本，叶子之间用 prev/next 串成双向链表，
+// 范围遍历只需沿着叶子链表走，不必在内部节点和孩子之间来回递归。
+//
+// 节点容量与 BTree 相同：非根节点的 key 数量在 [degree-1, 2*degree-1] 之间。
+// 插入和删除总是自底向上进行。Options 中只支持 Degree、Less 和 NodeBytes/KeyBytes，
+// 设置了其他选项时 NewBPlusTree 会 panic。
+type BPlusTree[K any, V any] struct {
+	root    *bplusNode[K, V]
+	options Options[K]
+	size    int
+}
+
+// bplusNode 是 B+ 树的节点。
+// 内部节点：children[i] 中的 key 都 < keys[i]，children[i+1] 中的 key 都 >= keys[i]；
+// 叶子：keys 与 values 一一对应。
+type bplusNode[K any, V any] struct {
+	isLeaf   bool
+	keys     []K
+	values   []V                // 仅叶子
+	children []*bplusNode[K, V] // 仅内部节点
+	prev     *bplusNode[K, V]   // 仅叶子：左边相邻的叶子
+	next     *bplusNode[K, V]   // 仅叶子：右边相邻的叶子
+}
+
+func NewBPlusTree[K any, V any](options Options[K]) *BPlusTree[K, V] {
+	options.requireOnly("BPlusTree")
+	return &BPlusTree[K, V]{
+		options: tuneDegree[K, V](options).validate(),
+	}
+}
+
+func (t *BPlusTree[K, V]) Len() int {
+	if t == nil {
+		return 0
+	}
+	return t.size
+}
+
+func (t *BPlusTree[K, V]) Clear() {
+	if t == nil {
+		return
+	}
+	t.root = nil
+	t.size = 0
+}
+
+// lowerBound 返回 keys 中第一个 >= key 的位置，以及该位置的 key 是否等于 key
+func (t *BPlusTree[K, V]) lowerBound(keys []K, key K) (int, bool) {
+	i := 0
+	for i < len(keys) && t.options.Less(keys[i], key) < 0 {
+		i++
+	}
+	return i, i < len(keys) && t.options.Less(keys[i], key) == 0
+}
+
+// childIndex 返回内部节点 n 中 key 所在的孩子下标：等于分隔 key 时走右边
+func (t *BPlusTree[K, V]) childIndex(n *bplusNode[K, V], key K) int {
+	i, found := t.lowerBound(n.keys, key)
+	if found {
+		i++
+	}
+	return i
+}
+
+// findLeaf 返回 key 所在（或应当插入）的叶子
+func (t *BPlusTree[K, V]) findLeaf(key K) *bplusNode[K, V] {
+	n := t.root
+	for !n.isLeaf {
+		n = n.children[t.childIndex(n, key)]
+	}
+	return n
+}
+
+// Get
+func (t *BPlusTree[K, V]) Get(key K) (V, bool) {
+	var zero V
+	if t == nil || t.root == nil {
+		return zero, false
+	}
+	leaf := t.findLeaf(key)
+	if i, found := t.lowerBound(leaf.keys, key); found {
+		return leaf.values[i], true
+	}
+	return zero, false
+}
+
+// Set
+func (t *BPlusTree[K, V]) Set(key K, value V) (old V, replaced bool) {
+	if t == nil {
+		return old, false
+	}
+	if t.root == nil {
+		t.root = &bplusNode[K, V]{isLeaf: true}
+	}
+
+	old, replaced = t.insert(t.root, key, value)
+	if !replaced {
+		t.size++
+	}
+
+	// 溢出传播到根：新建根并分裂旧根，树高加一
+	if len(t.root.keys) > 2*t.options.Degree-1 {
+		newRoot := &bplusNode[K, V]{children: []*bplusNode[K, V]{t.root}}
+		t.splitChild(newRoot, 0)
+		t.root = newRoot
+	}
+	return old, replaced
+}
+
+// insert 把 key 插入以 n 为根的子树，孩子溢出时在回溯阶段分裂
+func (t *BPlusTree[K, V]) insert(n *bplusNode[K, V], key K, value V) (old V, replaced bool) {
+	if n.isLeaf {
+		i, found := t.lowerBound(n.keys, key)
+		if found {
+			old = n.values[i]
+			n.values[i] = value
+			return old, true
+		}
+		n.keys = insertAt(n.keys, i, key)
+		n.values = insertAt(n.values, i, value)
+		return old, false
+	}
+
+	i := t.childIndex(n, key)
+	old, replaced = t.insert(n.children[i], key, value)
+	if len(n.children[i].keys) > 2*t.options.Degree-1 {
+		t.splitChild(n, i)
+	}
+	return old, replaced
+}
+
+// splitChild 分裂溢出的 parent.children[index]（2*degree 个 key）。
+// 叶子从中间切开，右半部分的第一个 key 复制一份上浮到 parent；
+// 内部节点与 BTree 相同，中间的 key 移动到 parent。
+func (t *BPlusTree[K, V]) splitChild(parent *bplusNode[K, V], index int) {
+	degree := t.options.Degree
+	child := parent.children[index]
+	right := &bplusNode[K, V]{isLeaf: child.isLeaf}
+
+	var sep K
+	if child.isLeaf {
+		right.keys = append(right.keys, child.keys[degree:]...)
+		right.values = append(right.values, child.values[degree:]...)
+		clear(child.values[degree:])
+		child.keys = child.keys[:degree]
+		child.values = child.values[:degree]
+		sep = right.keys[0]
+
+		// 把 right 接入叶子链表
+		right.prev = child
+		right.next = child.next
+		if child.next != nil {
+			child.next.prev = right
+		}
+		child.next = right
+	} else {
+		sep = child.keys[degree]
+		right.keys = append(right.keys, child.keys[degree+1:]...)
+		right.children = append(right.children, child.children[degree+1:]...)
+		clear(child.children[degree+1:])
+		child.keys = child.keys[:degree]
+		child.children = child.children[:degree+1]
+	}
+
+	parent.keys = insertAt(parent.keys, index, sep)
+	parent.children = insertAt(parent.children, index+1, right)
+}
+
+// Delete
+func (t *BPlusTree[K, V]) Delete(key K) (old V, deleted bool) {
+	if t == nil || t.root == nil {
+		return old, false
+	}
+	old, deleted = t.delete(t.root, key)
+	if !deleted {
+		return old, false
+	}
+	t.size--
+
+	// 根缩高：空叶子说明树空了，只有一个孩子的内部根让位给孩子
+	if len(t.root.keys) == 0 {
+		if t.root.isLeaf {
+			t.root = nil
+		} else {
+			t.root = t.root.children[0]
+		}
+	}
+	return old, true
+}
+
+// delete 在以 n 为根的子树中删除 key，孩子下溢时在回溯阶段借位或合并。
+// 内部节点里残留的分隔 key 即使已被删除也依然是合法的上下界，不需要更新。
+func (t *BPlusTree[K, V]) delete(n *bplusNode[K, V], key K) (old V, deleted bool) {
+	if n.isLeaf {
+		i, found := t.lowerBound(n.keys, key)
+		if !found {
+			return old, false
+		}
+		old = n.values[i]
+		n.keys = removeAt(n.keys, i)
+		n.values = removeAt(n.values, i)
+		return old, true
+	}
+
+	i := t.childIndex(n, key)
+	old, deleted = t.delete(n.children[i], key)
+	if deleted && len(n.children[i].keys) < t.options.Degree-1 {
+		t.fixChild(n, i)
+	}
+	return old, deleted
+}
+
+// fixChild 修复下溢的 parent.children[idx]：优先从兄弟借，否则与兄弟合并
+func (t *BPlusTree[K, V]) fixChild(parent *bplusNode[K, V], idx int) {
+	degree := t.options.Degree
+	if idx > 0 && len(parent.children[idx-1].keys) >= degree {
+		t.borrowFromLeft(parent, idx)
+		return
+	}
+	if idx+1 < len(parent.children) && len(parent.children[idx+1].keys) >= degree {
+		t.borrowFromRight(parent, idx)
+		return
+	}
+	if idx+1 < len(parent.children) {
+		t.mergeChildren(parent, idx)
+	} else {
+		t.mergeChildren(parent, idx-1)
+	}
+}
+
+func (t *BPlusTree[K, V]) borrowFromLeft(parent *bplusNode[K, V], idx int) {
+	child := parent.children[idx]
+	left := parent.children[idx-1]
+	last := len(left.keys) - 1
+
+	if child.isLeaf {
+		// 左兄弟的最后一个元素挪到 child 开头，分隔 key 更新为 child 新的第一个 key
+		child.keys = insertAt(child.keys, 0, left.keys[last])
+		child.values = insertAt(child.values, 0, left.values[last])
+		left.keys = removeAt(left.keys, last)
+		left.values = removeAt(left.values, last)
+		parent.keys[idx-1] = child.keys[0]
+		return
+	}
+
+	// 内部节点：分隔 key 下移，左兄弟的最后一个 key 上移
+	child.keys = insertAt(child.keys, 0, parent.keys[idx-1])
+	child.children = insertAt(child.children, 0, left.children[last+1])
+	parent.keys[idx-1] = left.keys[last]
+	left.keys = removeAt(left.keys, last)
+	left.children = removeAt(left.children, last+1)
+}
+
+func (t *BPlusTree[K, V]) borrowFromRight(parent *bplusNode[K, V], idx int) {
+	child := parent.children[idx]
+	right := parent.children[idx+1]
+
+	if child.isLeaf {
+		child.keys = append(child.keys, right.keys[0])
+		child.values = append(child.values, right.values[0])
+		right.keys = removeAt(right.keys, 0)
+		right.values = removeAt(right.values, 0)
+		parent.keys[idx] = right.keys[0]
+		return
+	}
+
+	child.keys = append(child.keys, parent.keys[idx])
+	child.children = append(child.children, right.children[0])
+	parent.keys[idx] = right.keys[0]
+	right.keys = removeAt(right.keys, 0)
+	right.children = removeAt(right.children, 0)
+}
+
+// mergeChildren 把 parent.children[idx+1] 合并进 parent.children[idx]。
+// 叶子合并时分隔 key 直接丢弃（它只是副本），并把右边的叶子从链表中摘除；
+// 内部节点合并时分隔 key 下移到两者之间。
+func (t *BPlusTree[K, V]) mergeChildren(parent *bplusNode[K, V], idx int) {
+	left := parent.children[idx]
+	right := parent.children[idx+1]
+
+	if left.isLeaf {
+		left.keys = append(left.keys, right.keys...)
+		left.values = append(left.values, right.values...)
+		left.next = right.next
+		if right.next != nil {
+			right.next.prev = left
+		}
+	} else {
+		left.keys = append(left.keys, parent.keys[idx])
+		left.keys = append(left.keys, right.keys...)
+		left.children = append(left.children, right.children...)
+	}
+
+	parent.keys = removeAt(parent.keys, idx)
+	parent.children = removeAt(parent.children, idx+1)
+}
+
+// firstLeaf 返回最左边的叶子
+func (t *BPlusTree[K, V]) firstLeaf() *bplusNode[K, V] {
+	n := t.root
+	for !n.isLeaf {
+		n = n.children[0]
+	}
+	return n
+}
+
+// Ascend 沿叶子链表按升序遍历所有元素，fn 返回 false 时停止
+func (t *BPlusTree[K, V]) Ascend(fn func(k K, v V) bool) {
+	if t == nil || t.root == nil {
+		return
+	}
+	for leaf := t.firstLeaf(); leaf != nil; leaf = leaf.next {
+		for i, k := range leaf.keys {
+			if !fn(k, leaf.values[i]) {
+				return
+			}
+		}
+	}
+}
+
+// AscendRange 按升序遍历 [greaterOrEqual, lessThan) 区间内的元素：
+// 只下沉一次找到起点叶子，之后沿链表向右扫描
+func (t *BPlusTree[K, V]) AscendRange(greaterOrEqual, lessThan K, fn func(k K, v V) bool) {
+	if t == nil || t.root == nil {
+		return
+	}
+	leaf := t.findLeaf(greaterOrEqual)
+	i, _ := t.lowerBound(leaf.keys, greaterOrEqual)
+	for ; leaf != nil; leaf, i = leaf.next, 0 {
+		for ; i < len(leaf.keys); i++ {
+			if t.options.Less(leaf.keys[i], lessThan) >= 0 {
+				return
+			}
+			if !fn(leaf.keys[i], leaf.values[i]) {
+				return
+			}
+		}
+	}
+}
+
+// Verify 检查 B+ 树的结构不变式：节点容量、key 有序且落在分隔 key 决定的区间内、
+// 叶子深度一致，以及叶子链表按顺序串起所有叶子且元素个数与 Len 一致。
+func (t *BPlusTree[K, V]) Verify() error {
+	if t == nil || t.root == nil {
+		return nil
+	}
+	leafDepth := -1
+	var leaves []*bplusNode[K, V]
+	if err := t.verifyNode(t.root, true, nil, nil, 0, &leafDepth, &leaves); err != nil {
+		return err
+	}
+
+	// 叶子链表必须与中序遍历得到的叶子顺序完全一致
+	count := 0
+	for i, leaf := range leaves {
+		var prev, next *bplusNode[K, V]
+		if i > 0 {
+			prev = leaves[i-1]
+		}
+		if i+1 < len(leaves) {
+			next = leaves[i+1]
+		}
+		if leaf.prev != prev || leaf.next != next {
+			return fmt.Errorf("btree: leaf %d is not linked to its neighbours", i)
+		}
+		count += len(leaf.keys)
+	}
+	if count != t.size {
+		return fmt.Errorf("btree: size is %d but leaves hold %d items", t.size, count)
+	}
+	return nil
+}
+
+// verifyNode 递归检查以 n 为根的子树，n 中的 key 必须落在 [minKey, maxKey) 内，nil 表示无界
+func (t *BPlusTree[K, V]) verifyNode(n *bplusNode[K, V], isRoot bool, minKey, maxKey *K, depth int, leafDepth *int, leaves *[]*bplusNode[K, V]) error {
+	if n == nil {
+		return fmt.Errorf("btree: encountered nil node at depth %d", depth)
+	}
+
+	degree := t.options.Degree
+	keyCount := len(n.keys)
+	if keyCount > 2*degree-1 || (!isRoot && keyCount < degree-1) {
+		return fmt.Errorf("btree: node at depth %d has %d keys, expect in [%d,%d]", depth, keyCount, degree-1, 2*degree-1)
+	}
+
+	for i, key := range n.keys {
+		if minKey != nil && t.options.Less(key, *minKey) < 0 {
+			return fmt.Errorf("btree: node at depth %d has key %v < minKey %v", depth, key, *minKey)
+		}
+		if maxKey != nil && t.options.Less(key, *maxKey) >= 0 {
+			return fmt.Errorf("btree: node at depth %d has key %v >= maxKey %v", depth, key, *maxKey)
+		}
+		if i > 0 && t.options.Less(n.keys[i-1], key) >= 0 {
+			return fmt.Errorf("btree: node at depth %d has unordered keys: %v >= %v", depth, n.keys[i-1], key)
+		}
+	}
+
+	if n.isLeaf {
+		if len(n.children) != 0 {
+			return fmt.Errorf("btree: leaf node at depth %d has children", depth)
+		}
+		if len(n.values) != keyCount {
+			return fmt.Errorf("btree: leaf node at depth %d has %d keys but %d values", depth, keyCount, len(n.values))
+		}
+		if *leafDepth == -1 {
+			*leafDepth = depth
+		} else if *leafDepth != depth {
+			return fmt.Errorf("btree: leaf nodes have different depths: %d and %d", *leafDepth, depth)
+		}
+		*leaves = append(*leaves, n)
+		return nil
+	}
+
+	if isRoot && keyCount == 0 {
+		return fmt.Errorf("btree: internal root has no keys")
+	}
+	if len(n.values) != 0 || n.prev != nil || n.next != nil {
+		return fmt.Errorf("btree: internal node at depth %d carries leaf data", depth)
+	}
+	if len(n.children) != keyCount+1 {
+		return fmt.Errorf("btree: internal node at depth %d has %d keys but %d children", depth, keyCount, len(n.children))
+	}
+	for i, child := range n.children {
+		childMin, childMax := minKey, maxKey
+		if i > 0 {
+			childMin = &n.keys[i-1]
+		}
+		if i < keyCount {
+			childMax = &n.keys[i]
+		}
+		if err := t.verifyNode(child, false, childMin, childMax, depth+1, leafDepth, leaves); err != nil {
+			return err
+		}
+	}
+	return nil
+}
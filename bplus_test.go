@@ -0,0 +1,109 @@
+package btree
+
+import (
+	"fmt"
+	"testing"
+)
+
+func buildBPlusTree(degree int, keys ...int) *BPlusTree[int, int] {
+	tree := NewBPlusTree[int, int](OptionsWithDegree(degree, intLess))
+	for _, k := range keys {
+		tree.Set(k, k)
+	}
+	return tree
+}
+
+func TestBPlusTreeNilAndClear(t *testing.T) {
+	var nilTree *BPlusTree[int, int]
+	if nilTree.Len() != 0 {
+		t.Fatalf("Len() on nil tree = %d, want 0", nilTree.Len())
+	}
+	if _, ok := nilTree.Get(1); ok {
+		t.Fatalf("Get on nil tree found a key")
+	}
+	if err := nilTree.Verify(); err != nil {
+		t.Fatalf("Verify() on nil tree = %v", err)
+	}
+
+	tree := buildBPlusTree(2, 1, 2, 3, 4, 5)
+	tree.Clear()
+	if tree.Len() != 0 || tree.root != nil {
+		t.Fatalf("tree not empty after Clear: Len()=%d", tree.Len())
+	}
+}
+
+func TestBPlusTreeRejectsUnsupportedOptions(t *testing.T) {
+	opts := OptionsWithDegree(2, intLess)
+	opts.NodeBytes = 256
+	NewBPlusTree[int, int](opts)
+
+	defer func() {
+		if r := recover(); r != "btree: BPlusTree does not support Options.LazyDelete" {
+			t.Fatalf("recover() = %v", r)
+		}
+	}()
+	opts.LazyDelete = true
+	NewBPlusTree[int, int](opts)
+}
+
+func TestBPlusTreeValuesOnlyInLeaves(t *testing.T) {
+	keys := make([]int, 200)
+	for i := range keys {
+		keys[i] = i
+	}
+	tree := buildBPlusTree(2, keys...)
+	if tree.root.isLeaf {
+		t.Fatalf("root should be internal after 200 inserts")
+	}
+
+	// 叶子链表覆盖全部元素，内部节点只保存 key 的副本
+	count := 0
+	for leaf := tree.firstLeaf(); leaf != nil; leaf = leaf.next {
+		count += len(leaf.values)
+	}
+	if count != len(keys) {
+		t.Fatalf("leaves hold %d values, want %d", count, len(keys))
+	}
+	if len(tree.root.values) != 0 {
+		t.Fatalf("internal root holds %d values, want 0", len(tree.root.values))
+	}
+}
+
+func TestBPlusTreeVerifyDetectsBrokenLinks(t *testing.T) {
+	keys := make([]int, 50)
+	for i := range keys {
+		keys[i] = i
+	}
+	tree := buildBPlusTree(2, keys...)
+	if err := tree.Verify(); err != nil {
+		t.Fatalf("Verify() = %v, want nil", err)
+	}
+
+	leaf := tree.firstLeaf()
+	leaf.next = leaf.next.next
+	if err := tree.Verify(); err == nil {
+		t.Fatalf("expected Verify() to fail on a broken leaf link, got nil")
+	}
+}
+
+// BenchmarkAscendRange 比较 BTree 的递归范围遍历与 BPlusTree 沿叶子链表的扫描
+func BenchmarkAscendRange(b *testing.B) {
+	const N = 100000
+	for _, impl := range implementations {
+		m := impl.new(32)
+		for i := 0; i < N; i++ {
+			m.Set(i, i)
+		}
+		for _, width := range []int{100, 10000} {
+			b.Run(fmt.Sprintf("%s/width=%d", impl.name, width), func(b *testing.B) {
+				lo := 0
+				for b.Loop() {
+					m.AscendRange(lo, lo+width, func(k, v int) bool {
+						return true
+					})
+					lo = (lo + 7919) % (N - width)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package btree
+
+import "slices"
+
+// relieveOverflow 处理 B*-tree 中 parent.children[idx] 的溢出：
+// 先经由父节点把一个 key 挪给有空位的兄弟；左右兄弟都满时，与一个兄弟一起 2 分 3。
+// 分出的三个节点各有约 2M/3 个 key（M = 2*degree-1），不会低于 minItems。
+func (t *BTree[K, V]) relieveOverflow(parent *node[K, V], idx int) {
+	maxItems := t.maxItems(false)
+
+	if idx > 0 && len(parent.children[idx-1].items) < maxItems {
+		// 左兄弟从右边借：child 的第一个 key 上移，父节点的分隔 key 下移到左兄弟
+		t.borrowFromRight(parent, idx-1)
+		return
+	}
+	if idx+1 < len(parent.children) && len(parent.children[idx+1].items) < maxItems {
+		t.borrowFromLeft(parent, idx+1)
+		return
+	}
+
+	if idx+1 < len(parent.children) {
+		t.redistribute(parent, idx, 2, 3)
+	} else {
+		t.redistribute(parent, idx-1, 2, 3)
+	}
+}
+
+// mergeStar 处理 B*-tree 中 parent.children[idx] 的下溢（此时相邻兄弟都没有多余的 key）：
+// 与两个兄弟一起 3 并 2；如果 parent 是只有两个孩子的根，则两个孩子合并成一个，由上层降低树高。
+// 返回下一个需要检查的孩子索引。
+func (t *BTree[K, V]) mergeStar(parent *node[K, V], idx int) int {
+	if len(parent.children) == 2 {
+		// 非根节点至少有 minItems+1 >= 3 个孩子，只有根会走到这里；
+		// 合并后恰好是 2*minItems 个 key，正好装得下新的根
+		t.redistribute(parent, 0, 2, 1)
+		return 0
+	}
+
+	first := min(max(idx-1, 0), len(parent.children)-3)
+	total := 2 // 两个分隔 key
+	for _, n := range parent.children[first : first+3] {
+		total += len(n.items)
+	}
+
+	// 下溢的节点只少一个 key，但 idx 位于两端时窗口里有一个兄弟没检查过，可能很满；
+	// 装不进两个节点时就三个节点之间均分
+	to := 2
+	if total-1 > 2*t.maxItems(false) {
+		to = 3
+	}
+	t.redistribute(parent, first, 3, to)
+	return first
+}
+
+// redistribute 把 parent.children[first:first+from] 连同它们之间的分隔 key
+// 重新均分成 to 个相邻的节点，用于 B*-tree 的 2 分 3 和 3 并 2。
+// 原有的节点对象会被复用，多出来的节点新建，多余的节点丢弃。
+func (t *BTree[K, V]) redistribute(parent *node[K, V], first, from, to int) {
+	nodes := parent.children[first : first+from]
+	isLeaf := nodes[0].isLeaf
+
+	// 按顺序收集所有 key（包括分隔 key，连同墓碑标记）和孩子指针
+	all := &node[K, V]{}
+	var children []*node[K, V]
+	for j, n := range nodes {
+		if j > 0 {
+			all.appendItems(parent, first+j-1, first+j)
+		}
+		all.appendItems(n, 0, len(n.items))
+		children = append(children, n.children...)
+	}
+
+	out := make([]*node[K, V], to)
+	seps := &node[K, V]{items: make([]item[K, V], 0, to-1)}
+	total := len(all.items) - (to - 1) // 留在节点中的 key 数
+	pos, childPos := 0, 0
+	for j := range out {
+		var n *node[K, V]
+		if j < from {
+			n = t.mutableChild(parent, first+j)
+			n.truncateItems(0)
+			clear(n.children)
+		} else {
+			n = t.newNode(isLeaf)
+		}
+		size := total / to
+		if j < total%to {
+			size++
+		}
+
+		n.appendItems(all, pos, pos+size)
+		pos += size
+		if !isLeaf {
+			n.children = append(n.children[:0], children[childPos:childPos+size+1]...)
+			childPos += size + 1
+		}
+		if j < to-1 {
+			seps.appendItems(all, pos, pos+1)
+			pos++
+		}
+		t.refresh(n)
+		out[j] = n
+	}
+
+	// 节点变少时，多出来的节点不再使用
+	var dropped []*node[K, V]
+	if to < from {
+		dropped = slices.Clone(parent.children[first+to : first+from])
+	}
+	if parent.deadFlags() != nil || seps.deadFlags() != nil {
+		dead := slices.Replace(parent.syncDead(), first, first+from-1, seps.syncDead()...)
+		parent.ext.dead = dead
+	}
+	parent.items = slices.Replace(parent.items, first, first+from-1, seps.items...)
+	parent.children = slices.Replace(parent.children, first, first+from, out...)
+	for _, n := range dropped {
+		t.freeNode(n)
+	}
+}
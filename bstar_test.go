@@ -0,0 +1,191 @@
+package btree
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"testing"
+	"unsafe"
+)
+
+func TestBStarMinItems(t *testing.T) {
+	tests := []struct {
+		degree, min, rootMax int
+	}{
+		{2, 2, 4},    // M=3
+		{3, 3, 6},    // M=5
+		{4, 4, 8},    // M=7
+		{32, 42, 84}, // M=63
+	}
+	for _, tc := range tests {
+		tree := buildTreeWithStrategy(tc.degree, BStar)
+		if got := tree.minItems(); got != tc.min {
+			t.Fatalf("degree %d: minItems() = %d, want %d", tc.degree, got, tc.min)
+		}
+		if got := tree.maxItems(true); got != tc.rootMax {
+			t.Fatalf("degree %d: maxItems(root) = %d, want %d", tc.degree, got, tc.rootMax)
+		}
+	}
+}
+
+func TestBStarSetGetDelete(t *testing.T) {
+	for _, degree := range []int{2, 3, 5, 16} {
+		r := rand.New(rand.NewSource(int64(degree)))
+		tree := buildTreeWithStrategy(degree, BStar)
+		model := make(map[int]int)
+
+		for i := 0; i < 4000; i++ {
+			k := r.Intn(600)
+			if r.Intn(3) == 0 {
+				old, deleted := tree.Delete(k)
+				want, ok := model[k]
+				if deleted != ok || old != want {
+					t.Fatalf("degree %d: Delete(%d) = (%d,%v), want (%d,%v)", degree, k, old, deleted, want, ok)
+				}
+				delete(model, k)
+			} else {
+				old, replaced := tree.Set(k, i)
+				want, ok := model[k]
+				if replaced != ok || old != want {
+					t.Fatalf("degree %d: Set(%d) = (%d,%v), want (%d,%v)", degree, k, old, replaced, want, ok)
+				}
+				model[k] = i
+			}
+			if i%100 == 0 {
+				assertVerify(t, tree)
+			}
+		}
+
+		var want []int
+		for k := range model {
+			want = append(want, k)
+		}
+		slices.Sort(want)
+		assertVerify(t, tree)
+		assertKeys(t, tree, want)
+	}
+}
+
+func TestBStarSequentialAndDeleteAll(t *testing.T) {
+	const N = 1000
+	keys := make([]int, N)
+	for i := range keys {
+		keys[i] = i
+	}
+	tree := buildTreeWithStrategy(3, BStar, keys...)
+	assertVerify(t, tree)
+	assertKeys(t, tree, keys)
+
+	for i := N - 1; i >= 0; i -= 2 {
+		tree.Delete(i)
+	}
+	assertVerify(t, tree)
+	for i := 0; i < N; i += 2 {
+		tree.Delete(i)
+		assertVerify(t, tree)
+	}
+	if tree.Len() != 0 || tree.root != nil {
+		t.Fatalf("tree should be empty, got Len()=%d root=%+v", tree.Len(), tree.root)
+	}
+}
+
+func TestBStarDeleteIf(t *testing.T) {
+	const N = 800
+	keys := make([]int, N)
+	for i := range keys {
+		keys[i] = i
+	}
+	tree := buildTreeWithStrategy(4, BStar, keys...)
+
+	tree.DeleteIf(func(k, v int) bool { return k%5 == 0 })
+
+	var want []int
+	for _, k := range keys {
+		if k%5 != 0 {
+			want = append(want, k)
+		}
+	}
+	assertVerify(t, tree)
+	assertKeys(t, tree, want)
+}
+
+// 节点低于 2/3 满时，B* 模式下的 Verify 必须报错，而普通模式下是合法的
+func TestVerify_BStarLowerBound(t *testing.T) {
+	root := internalNode([]int{30}, leaf(10), leaf(40, 50))
+	tree := treeFromRoot(root, 2)
+	assertVerify(t, tree)
+
+	tree.options.Strategy = BStar
+	if err := tree.Verify(); err == nil {
+		t.Fatalf("expected Verify() to fail on a node below 2/3 full, got nil")
+	}
+}
+
+func TestBStarDenserThanBTree(t *testing.T) {
+	for _, degree := range []int{3, 8, 32} {
+		r := rand.New(rand.NewSource(1))
+		keys := make([]int, 50000)
+		for i := range keys {
+			keys[i] = r.Int()
+		}
+
+		plain := buildTreeWithStrategy(degree, BottomUp, keys...).Stats()
+		star := buildTreeWithStrategy(degree, BStar, keys...).Stats()
+		t.Logf("degree %d: bottom-up %+v, B* %+v", degree, plain, star)
+
+		if star.Nodes >= plain.Nodes {
+			t.Fatalf("degree %d: B* uses %d nodes, want fewer than %d", degree, star.Nodes, plain.Nodes)
+		}
+		if star.FillFactor < 2.0/3 {
+			t.Fatalf("degree %d: B* fill factor %.3f, want at least 2/3", degree, star.FillFactor)
+		}
+		if star.Height > plain.Height {
+			t.Fatalf("degree %d: B* height %d, want at most %d", degree, star.Height, plain.Height)
+		}
+	}
+}
+
+// nodeBytes 估算以 n 为根的子树占用的内存：节点头加上 items/children 的底层数组
+func nodeBytes[K any, V any](n *node[K, V]) int {
+	total := int(unsafe.Sizeof(*n)) +
+		cap(n.items)*int(unsafe.Sizeof(item[K, V]{})) +
+		cap(n.children)*int(unsafe.Sizeof(n))
+	for _, child := range n.children {
+		total += nodeBytes(child)
+	}
+	return total
+}
+
+// BenchmarkStrategyBuild 随机插入 N 个 key，报告每种策略建出的树的高度、节点数、装载率和内存占用
+func BenchmarkStrategyBuild(b *testing.B) {
+	const N = 100000
+	r := rand.New(rand.NewSource(1))
+	keys := make([]int, N)
+	for i := range keys {
+		keys[i] = r.Int()
+	}
+
+	for _, degree := range []int{4, 32} {
+		for _, s := range []struct {
+			name     string
+			strategy Strategy
+		}{
+			{"Preemptive", Preemptive},
+			{"BottomUp", BottomUp},
+			{"BStar", BStar},
+		} {
+			b.Run(fmt.Sprintf("degree=%d/%s", degree, s.name), func(b *testing.B) {
+				b.ReportAllocs()
+				var tree *BTree[int, int]
+				for b.Loop() {
+					tree = buildTreeWithStrategy(degree, s.strategy, keys...)
+				}
+				st := tree.Stats()
+				b.ReportMetric(float64(st.Height), "height")
+				b.ReportMetric(float64(st.Nodes), "nodes")
+				b.ReportMetric(st.FillFactor, "fill")
+				b.ReportMetric(float64(nodeBytes(tree.root))/N, "bytes/item")
+			})
+		}
+	}
+}
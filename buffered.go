@@ -0,0 +1,721 @@
+package btree
+
+import (
+	"fmt"
+	"slices"
+)
+
+// 默认每个内部节点缓冲 defaultBufferFactor*degree 条消息
+const defaultBufferFactor = 4
+
+// BufferedBTree 是写优化的 Bε-tree：内部节点带有消息缓冲区，Set/Delete 只把一条消息追加到写日志，
+// 日志满时排序后整批并入根的缓冲区；缓冲区满时按孩子分批整体下推，直到叶子才真正落地。
+// 一次下推分摊了整条路径上的查找和结构调整，适合写多读少的导入场景。
+//
+// 消息是带删除标记的元素（见 message）。缓冲区按 key 有序，每个 key 最多一条，
+// 越靠近根的消息越新。Get、Ascend 沿路径合并尚未落地的消息。
+// Set/Delete 是盲写，不查找旧值，因此不像 BTree 那样返回旧值；只有落地时才知道一条消息是否改变元素个数，
+// 所以 Len 会先 Flush。
+//
+// 结构调整复用 BTree 的 splitChildAt/fixChild。删除消息落在内部节点的 key 上时只留下墓碑，
+// Flush 时统一 Compact。
+type BufferedBTree[K any, V any] struct {
+	tree       *BTree[K, V]
+	bufferSize int
+	pending    int             // 写日志和所有缓冲区中的消息数
+	log        []message[K, V] // 尚未排序的新消息，按写入顺序追加
+	scratch    []message[K, V] // enqueue 合并缓冲区时复用
+	merged     []item[K, V]    // mergeLeaf 归并叶子时复用
+}
+
+// message 是一条尚未落地的写入：deleted 为 true 表示删除 key，否则把 key 设为 value
+type message[K any, V any] struct {
+	key     K
+	value   V
+	deleted bool
+}
+
+// NewBuffered 创建一个 BufferedBTree。Options 中的 Strategy、LazyDelete、CompactThreshold 和 FastAppend 被忽略。
+func NewBuffered[K any, V any](options Options[K]) *BufferedBTree[K, V] {
+	// 叶子会一次性收到一批消息，自底向上修复溢出/下溢；内部节点上的删除留下墓碑
+	options.Strategy = BottomUp
+	options.LazyDelete = true
+	options.CompactThreshold = 0
+	options.FastAppend = false
+	options = tuneDegree[K, V](options).validate()
+	if options.BufferSize < 0 {
+		panic("btree: BufferSize must be >= 0")
+	}
+	size := options.BufferSize
+	if size == 0 {
+		size = defaultBufferFactor * options.Degree
+	}
+	return &BufferedBTree[K, V]{
+		tree:       NewWithOptions[K, V](options),
+		bufferSize: size,
+	}
+}
+
+// Len 返回元素个数。它会先 Flush 所有缓冲的消息，因此和 Flush 一样会修改树，不能与读操作并发调用。
+func (b *BufferedBTree[K, V]) Len() int {
+	if b == nil {
+		return 0
+	}
+	b.Flush()
+	return b.tree.Len()
+}
+
+// Pending 返回缓冲区中尚未落地的消息数
+func (b *BufferedBTree[K, V]) Pending() int {
+	if b == nil {
+		return 0
+	}
+	return b.pending
+}
+
+func (b *BufferedBTree[K, V]) Clear() {
+	if b == nil {
+		return
+	}
+	b.tree.Clear()
+	clear(b.log)
+	b.log = b.log[:0]
+	b.pending = 0
+}
+
+// Set 写入 key，只追加一条消息，不读取旧值
+func (b *BufferedBTree[K, V]) Set(key K, value V) {
+	if b == nil {
+		return
+	}
+	b.put(message[K, V]{key: key, value: value})
+}
+
+// Delete 删除 key，key 不存在时消息落地后什么也不做
+func (b *BufferedBTree[K, V]) Delete(key K) {
+	if b == nil {
+		return
+	}
+	b.put(message[K, V]{key: key, deleted: true})
+}
+
+func (b *BufferedBTree[K, V]) put(msg message[K, V]) {
+	b.log = append(b.log, msg)
+	b.pending++
+	if len(b.log) >= b.bufferSize {
+		b.drain()
+	}
+}
+
+// drain 把写日志排序去重（同一个 key 保留最后写入的消息）后整批交给根
+func (b *BufferedBTree[K, V]) drain() {
+	t := b.tree
+	slices.SortStableFunc(b.log, func(x, y message[K, V]) int {
+		return t.cmp(x.key, y.key)
+	})
+	msgs := b.log[:0]
+	for i, msg := range b.log {
+		if i+1 < len(b.log) && t.equal(msg.key, b.log[i+1].key) {
+			continue
+		}
+		msgs = append(msgs, msg)
+	}
+	b.pending -= len(b.log)
+
+	if t.root == nil {
+		t.root = t.newNode(true)
+	}
+	t.root = t.mutable(t.root)
+	b.enqueue(t.root, msgs)
+	clear(b.log)
+	b.log = b.log[:0]
+	b.fixRoot()
+}
+
+// enqueue 把按 key 有序的 msgs 交给 n：叶子直接应用；内部节点上 key 已存在的直接应用，
+// 其余并入 n 的缓冲区，msgs 比缓冲区中已有的消息新。不会触发下推。
+func (b *BufferedBTree[K, V]) enqueue(n *node[K, V], msgs []message[K, V]) {
+	t := b.tree
+	if n.isLeaf {
+		b.mergeLeaf(n, msgs)
+		return
+	}
+
+	// 消息远少于缓冲区时逐条二分插入，否则整体归并
+	if len(msgs)*8 < len(n.messages()) {
+		for _, msg := range msgs {
+			if i, found := t.findIndex(n, msg.key); found {
+				b.apply(n, i, msg)
+				continue
+			}
+			j, found := b.findMessage(n, msg.key)
+			if found {
+				n.messages()[j] = msg
+				continue
+			}
+			n.setMessages(insertAt(n.messages(), j, msg))
+			b.pending++
+		}
+		return
+	}
+
+	merged := b.scratch[:0]
+	buf, j, p := n.messages(), 0, 0
+	for _, msg := range msgs {
+		for p < len(n.items) && t.lessThan(n.items[p].key, msg.key) {
+			p++
+		}
+		if p < len(n.items) && t.equal(n.items[p].key, msg.key) {
+			b.apply(n, p, msg)
+			continue
+		}
+		for j < len(buf) && t.lessThan(buf[j].key, msg.key) {
+			merged = append(merged, buf[j])
+			j++
+		}
+		if j < len(buf) && t.equal(buf[j].key, msg.key) {
+			j++ // 旧消息被覆盖
+		} else {
+			b.pending++
+		}
+		merged = append(merged, msg)
+	}
+	merged = append(merged, buf[j:]...)
+	n.setMessages(append(n.messages()[:0], merged...))
+	clear(merged)
+	b.scratch = merged[:0]
+}
+
+// mergeLeaf 把有序的 msgs 与叶子 n 的元素归并，叶子可能因此溢出或下溢，由调用方修复。
+// 内部节点的墓碑可能随合并落到叶子上，归并时顺便丢掉，归并后的叶子中没有墓碑。
+func (b *BufferedBTree[K, V]) mergeLeaf(n *node[K, V], msgs []message[K, V]) {
+	t := b.tree
+	merged := b.merged[:0]
+	items, i := n.items, 0
+	// keep 把 items[i] 移到 merged，墓碑直接丢掉
+	keep := func() {
+		if n.isDead(i) {
+			t.tombstones--
+		} else {
+			merged = append(merged, items[i])
+		}
+		i++
+	}
+	for _, msg := range msgs {
+		for i < len(items) && t.lessThan(items[i].key, msg.key) {
+			keep()
+		}
+		if i < len(items) && t.equal(items[i].key, msg.key) {
+			if n.isDead(i) {
+				t.tombstones--
+			} else {
+				t.size--
+			}
+			i++
+		}
+		if !msg.deleted {
+			merged = append(merged, item[K, V]{key: msg.key, value: msg.value})
+			t.size++
+		}
+	}
+	for i < len(items) {
+		keep()
+	}
+	n.truncateItems(0)
+	n.items = append(n.items, merged...)
+	if n.ext != nil {
+		n.ext.dead = nil
+	}
+	clear(merged)
+	b.merged = merged[:0]
+}
+
+// apply 把消息应用到内部节点已存在的 n.items[i] 上，叶子上的消息由 mergeLeaf 处理
+func (b *BufferedBTree[K, V]) apply(n *node[K, V], i int, msg message[K, V]) {
+	t := b.tree
+	if !msg.deleted {
+		if _, replaced := t.overwrite(n, i, msg.value); !replaced {
+			t.size++
+		}
+		return
+	}
+	if n.isDead(i) {
+		return
+	}
+	// 物理删除内部节点的 key 要动到子树，而子树里还有缓冲的消息，先留下墓碑
+	var zero V
+	n.items[i].value = zero
+	n.setDead(i, true)
+	t.tombstones++
+	t.size--
+}
+
+// flush 清空 n 的缓冲区：从右往左每次取出发往同一个孩子的一批消息下推给该孩子，并修复该孩子的结构。
+// 一次下推全部消息而不是只推最多的一批，每条消息在每一层都是整批移动。
+// 修复后 n 自身可能溢出或下溢，由 n 的父节点（或 fixRoot）处理。
+func (b *BufferedBTree[K, V]) flush(n *node[K, V]) {
+	t := b.tree
+	for buf := n.messages(); len(buf) > 0; buf = n.messages() {
+		// 缓冲区最后一条消息所属的孩子，以及发往它的消息的起点
+		last := len(buf) - 1
+		i, _ := t.findIndex(n, buf[last].key)
+		lo := 0
+		if i > 0 {
+			lo, _ = b.searchMessages(buf[:last], n.items[i-1].key)
+		}
+
+		b.pending -= len(buf) - lo
+		child := t.mutableChild(n, i)
+		b.enqueue(child, buf[lo:]) // 递归下推不会改动 n 的缓冲区
+		if !child.isLeaf && len(child.messages()) > b.bufferSize {
+			b.flush(child)
+		}
+		clear(buf[lo:])
+		n.setMessages(buf[:lo])
+		b.restructure(n, i)
+	}
+}
+
+// restructure 修复 n.children[i] 的溢出或下溢。
+// 内部孩子的缓冲区在调整前取出，调整后按新的分隔 key 重新分配。
+func (b *BufferedBTree[K, V]) restructure(n *node[K, V], i int) {
+	t := b.tree
+	child := n.children[i]
+	overflow := len(child.items) > t.maxItems(false)
+	if !overflow && len(child.items) >= t.minItems() {
+		return
+	}
+
+	var msgs []message[K, V]
+	if !child.isLeaf {
+		msgs = b.takeChildBuffers(n)
+	}
+	if overflow {
+		b.splitOverflow(n, i)
+	} else {
+		lone := !child.isLeaf && len(child.children) == 1
+		for len(n.children) > 1 && i < len(n.children) && len(n.children[i].items) < t.minItems() {
+			i = t.fixChild(n, i)
+		}
+		if lone {
+			b.repairLone(n)
+		}
+	}
+	b.redistribute(n, msgs)
+	b.settle(n)
+}
+
+// repairLone 修复 n 的孙子中下溢的那个。墓碑落到叶子后会被丢掉，叶子可能远低于下限，
+// 它的父节点合并到只剩它一个孩子时无法修复它；父节点被借位或合并进兄弟之后，它有了兄弟，才能在这里修复。
+func (b *BufferedBTree[K, V]) repairLone(n *node[K, V]) {
+	t := b.tree
+	for j, c := range n.children {
+		for k, g := range c.children {
+			if len(g.items) < t.minItems() {
+				b.restructure(t.mutableChild(n, j), k)
+				b.restructure(n, j) // c 可能因合并而下溢
+				return
+			}
+		}
+	}
+}
+
+// splitOverflow 反复分裂 n.children[i]，直到分出的每个节点都不超过上限。
+// 一批消息可能让叶子多出很多 key，左边尽量装满，右边剩下的继续分裂。
+func (b *BufferedBTree[K, V]) splitOverflow(n *node[K, V], i int) {
+	t := b.tree
+	maxItems := t.maxItems(false)
+	for ; len(n.children[i].items) > maxItems; i++ {
+		t.splitChildAt(n, i, min(len(n.children[i].items)/2, maxItems-1))
+	}
+}
+
+// takeChildBuffers 取出 n 所有孩子的缓冲区。孩子的 key 区间互不相交且有序，拼接后仍然有序。
+func (b *BufferedBTree[K, V]) takeChildBuffers(n *node[K, V]) []message[K, V] {
+	var msgs []message[K, V]
+	for i, child := range n.children {
+		if len(child.messages()) == 0 {
+			continue
+		}
+		child = b.tree.mutableChild(n, i)
+		buf := child.messages()
+		msgs = append(msgs, buf...)
+		clear(buf)
+		child.setMessages(buf[:0])
+	}
+	b.pending -= len(msgs)
+	return msgs
+}
+
+// redistribute 按 n 当前的分隔 key 把 msgs 分给 n 的孩子（或应用到 n 自身）。
+// 内部孩子的缓冲区可能因此暂时超过上限，下次收到消息时再下推；叶子孩子收到消息后立即修复。
+func (b *BufferedBTree[K, V]) redistribute(n *node[K, V], msgs []message[K, V]) {
+	t := b.tree
+	for len(msgs) > 0 {
+		i, found := t.findIndex(n, msgs[0].key)
+		if found {
+			b.apply(n, i, msgs[0])
+			msgs = msgs[1:]
+			continue
+		}
+		end := len(msgs)
+		if i < len(n.items) {
+			end = 1
+			for end < len(msgs) && t.lessThan(msgs[end].key, n.items[i].key) {
+				end++
+			}
+		}
+		child := t.mutableChild(n, i)
+		b.enqueue(child, msgs[:end])
+		msgs = msgs[end:]
+		if child.isLeaf {
+			// 消息直接落到叶子上，叶子可能溢出或下溢，立即修复；之后的消息按新的分隔 key 分配
+			b.restructure(n, i)
+		}
+	}
+}
+
+// settle 应用 n 的缓冲区中 key 与 n.items 相同的消息：
+// 分裂或借位会把新的 key 移到 n 中，而缓冲区中的消息总是比它新。
+func (b *BufferedBTree[K, V]) settle(n *node[K, V]) {
+	if len(n.messages()) == 0 {
+		return
+	}
+	for i := range n.items {
+		if j, found := b.findMessage(n, n.items[i].key); found {
+			buf := n.messages()
+			b.apply(n, i, buf[j])
+			n.setMessages(slices.Delete(buf, j, j+1))
+			b.pending--
+		}
+	}
+}
+
+// fixRoot 处理根的溢出、空根和根缓冲区满的情况，直到根满足约束
+func (b *BufferedBTree[K, V]) fixRoot() {
+	t := b.tree
+	for t.root != nil {
+		root := t.root
+		switch {
+		case len(root.items) > t.maxItems(true):
+			// 分裂根并增长树高，根的缓冲区分给分裂出的节点
+			msgs := root.messages()
+			root.setMessages(nil)
+			b.pending -= len(msgs)
+			t.root = t.newNode(false)
+			t.root.children = append(t.root.children, root)
+			b.splitOverflow(t.root, 0)
+			b.redistribute(t.root, msgs)
+		case len(root.items) == 0:
+			// 降低树高，根的缓冲区并入唯一的孩子
+			if root.isLeaf {
+				t.root = nil
+				t.freeNode(root)
+				return
+			}
+			msgs := slices.Clone(root.messages())
+			b.pending -= len(msgs)
+			t.root = t.mutable(root.children[0])
+			t.freeNode(root)
+			b.enqueue(t.root, msgs)
+		case len(root.messages()) > b.bufferSize:
+			b.flush(root)
+		default:
+			return
+		}
+	}
+}
+
+// Flush 把所有缓冲的消息下推到底，并清理删除留下的墓碑
+func (b *BufferedBTree[K, V]) Flush() {
+	if b == nil {
+		return
+	}
+	t := b.tree
+	if len(b.log) > 0 {
+		b.drain()
+	}
+	for b.pending > 0 {
+		t.root = t.mutable(t.root)
+		b.flushAll(t.root)
+		b.fixRoot()
+	}
+	t.Compact()
+}
+
+// flushAll 清空以 n 为根的子树中的缓冲区。结构调整可能把消息移到已经处理过的孩子，
+// 由 Flush 重复调用直到没有消息。
+func (b *BufferedBTree[K, V]) flushAll(n *node[K, V]) {
+	if n.isLeaf {
+		return
+	}
+	b.flush(n)
+	for i := 0; i < len(n.children); i++ {
+		if n.children[i].isLeaf {
+			break
+		}
+		b.flushAll(b.tree.mutableChild(n, i))
+		b.restructure(n, i)
+	}
+}
+
+// messages 返回 n 的缓冲区。缓冲区放在 ext 中，叶子和还没收到过消息的内部节点没有 ext，返回 nil。
+func (n *node[K, V]) messages() []message[K, V] {
+	if n.ext == nil {
+		return nil
+	}
+	return n.ext.buffer
+}
+
+// setMessages 替换 n 的缓冲区，缓冲区为空时不为它分配 ext
+func (n *node[K, V]) setMessages(msgs []message[K, V]) {
+	if n.ext == nil && len(msgs) == 0 {
+		return
+	}
+	n.extension().buffer = msgs
+}
+
+// findMessage 在 n 的缓冲区中二分查找 key
+func (b *BufferedBTree[K, V]) findMessage(n *node[K, V], key K) (int, bool) {
+	return b.searchMessages(n.messages(), key)
+}
+
+func (b *BufferedBTree[K, V]) searchMessages(msgs []message[K, V], key K) (int, bool) {
+	return slices.BinarySearchFunc(msgs, key, func(msg message[K, V], key K) int {
+		return b.tree.cmp(msg.key, key)
+	})
+}
+
+// Get 先从新到旧扫描写日志，再从根向下查找，路径上遇到的第一条消息或元素就是最新的结果
+func (b *BufferedBTree[K, V]) Get(key K) (V, bool) {
+	var zero V
+	if b == nil {
+		return zero, false
+	}
+	t := b.tree
+	for i := len(b.log) - 1; i >= 0; i-- {
+		if msg := b.log[i]; t.equal(msg.key, key) {
+			if msg.deleted {
+				return zero, false
+			}
+			return msg.value, true
+		}
+	}
+	for n := t.root; n != nil; {
+		i, found := t.findIndex(n, key)
+		if found {
+			if n.isDead(i) {
+				return zero, false
+			}
+			return n.items[i].value, true
+		}
+		if n.isLeaf {
+			break
+		}
+		if j, ok := b.findMessage(n, key); ok {
+			msg := n.messages()[j]
+			if msg.deleted {
+				return zero, false
+			}
+			return msg.value, true
+		}
+		n = n.children[i]
+	}
+	return zero, false
+}
+
+// Ascend 按升序遍历，缓冲的消息与树中的元素合并后再交给 fn，fn 返回 false 时停止
+func (b *BufferedBTree[K, V]) Ascend(fn func(k K, v V) bool) {
+	if b == nil {
+		return
+	}
+	b.ascendWithLog(nil, nil, fn)
+}
+
+// AscendRange 按升序遍历 [greaterOrEqual, lessThan) 区间内的元素，fn 返回 false 时停止
+func (b *BufferedBTree[K, V]) AscendRange(greaterOrEqual, lessThan K, fn func(k K, v V) bool) {
+	if b == nil {
+		return
+	}
+	b.ascendWithLog(&greaterOrEqual, &lessThan, fn)
+}
+
+// ascendWithLog 把写日志排序去重后作为最新的一层消息参与遍历，不改动日志本身
+func (b *BufferedBTree[K, V]) ascendWithLog(lo, hi *K, fn func(k K, v V) bool) {
+	t := b.tree
+	var msgs []message[K, V]
+	if len(b.log) > 0 {
+		sorted := slices.Clone(b.log)
+		slices.SortStableFunc(sorted, func(x, y message[K, V]) int {
+			return t.cmp(x.key, y.key)
+		})
+		for i, msg := range sorted {
+			if i+1 == len(sorted) || !t.equal(msg.key, sorted[i+1].key) {
+				msgs = append(msgs, msg)
+			}
+		}
+	}
+	if t.root == nil {
+		// 树还是空的，只有日志中的消息
+		for _, msg := range msgs {
+			if !b.emit(msg, lo, hi, fn) {
+				return
+			}
+		}
+		return
+	}
+	b.ascend(t.root, msgs, lo, hi, fn)
+}
+
+// ascend 中序遍历以 n 为根的子树。msgs 是祖先缓冲区中落在该子树区间内的消息，比 n 中的一切都新。
+// lo/hi 为 nil 表示不限制。
+func (b *BufferedBTree[K, V]) ascend(n *node[K, V], msgs []message[K, V], lo, hi *K, fn func(k K, v V) bool) bool {
+	t := b.tree
+	if n.isLeaf {
+		// 叶子的元素与消息按 key 归并，相同的 key 以消息为准
+		i := 0
+		for i < len(n.items) || len(msgs) > 0 {
+			var msg message[K, V]
+			switch {
+			case len(msgs) == 0 || (i < len(n.items) && t.lessThan(n.items[i].key, msgs[0].key)):
+				msg = itemMessage(n, i)
+				i++
+			case i < len(n.items) && t.equal(n.items[i].key, msgs[0].key):
+				msg, msgs = msgs[0], msgs[1:]
+				i++
+			default:
+				msg, msgs = msgs[0], msgs[1:]
+			}
+			if !b.emit(msg, lo, hi, fn) {
+				return false
+			}
+		}
+		return true
+	}
+
+	msgs = b.mergeMessages(msgs, n.messages())
+	for i := 0; i <= len(n.items); i++ {
+		end := len(msgs)
+		if i < len(n.items) {
+			end = 0
+			for end < len(msgs) && t.lessThan(msgs[end].key, n.items[i].key) {
+				end++
+			}
+		}
+		childMsgs := msgs[:end]
+		msgs = msgs[end:]
+
+		if i == len(n.items) {
+			return b.ascend(n.children[i], childMsgs, lo, hi, fn)
+		}
+		it := itemMessage(n, i)
+		if len(msgs) > 0 && t.equal(msgs[0].key, it.key) {
+			it, msgs = msgs[0], msgs[1:]
+		}
+		// children[i] 和 items[i] 都在区间左侧时整体跳过
+		if lo != nil && t.lessThan(it.key, *lo) {
+			continue
+		}
+		if !b.ascend(n.children[i], childMsgs, lo, hi, fn) || !b.emit(it, lo, hi, fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// itemMessage 把 n.items[i] 表示成一条消息，墓碑对应删除消息
+func itemMessage[K any, V any](n *node[K, V], i int) message[K, V] {
+	return message[K, V]{key: n.items[i].key, value: n.items[i].value, deleted: n.isDead(i)}
+}
+
+// emit 把一个元素或消息交给 fn：删除和区间左侧的跳过，到达区间右端时返回 false 停止遍历
+func (b *BufferedBTree[K, V]) emit(it message[K, V], lo, hi *K, fn func(k K, v V) bool) bool {
+	t := b.tree
+	switch {
+	case hi != nil && !t.lessThan(it.key, *hi):
+		return false
+	case lo != nil && t.lessThan(it.key, *lo), it.deleted:
+		return true
+	}
+	return fn(it.key, it.value)
+}
+
+// mergeMessages 归并两段有序消息，相同的 key 保留 newer 中的消息
+func (b *BufferedBTree[K, V]) mergeMessages(newer, older []message[K, V]) []message[K, V] {
+	if len(newer) == 0 {
+		return older
+	}
+	if len(older) == 0 {
+		return newer
+	}
+	t := b.tree
+	merged := make([]message[K, V], 0, len(newer)+len(older))
+	for len(newer) > 0 && len(older) > 0 {
+		switch c := t.cmp(newer[0].key, older[0].key); {
+		case c < 0:
+			merged, newer = append(merged, newer[0]), newer[1:]
+		case c > 0:
+			merged, older = append(merged, older[0]), older[1:]
+		default:
+			merged, newer, older = append(merged, newer[0]), newer[1:], older[1:]
+		}
+	}
+	merged = append(merged, newer...)
+	return append(merged, older...)
+}
+
+// Verify 检查底层 B-Tree 的不变式，以及每个缓冲区有序、落在所属节点的区间内、不与节点的 key 重复
+func (b *BufferedBTree[K, V]) Verify() error {
+	if b == nil {
+		return nil
+	}
+	if err := b.tree.Verify(); err != nil {
+		return err
+	}
+	count := len(b.log)
+	if b.tree.root != nil {
+		if err := b.verifyBuffers(b.tree.root, nil, nil, &count); err != nil {
+			return err
+		}
+	}
+	if count != b.pending {
+		return fmt.Errorf("btree: pending is %d but buffers hold %d messages", b.pending, count)
+	}
+	return nil
+}
+
+func (b *BufferedBTree[K, V]) verifyBuffers(n *node[K, V], minKey, maxKey *K, count *int) error {
+	t := b.tree
+	if n.isLeaf {
+		if buf := n.messages(); len(buf) != 0 {
+			return fmt.Errorf("btree: leaf holds %d buffered messages", len(buf))
+		}
+		return nil
+	}
+	buf := n.messages()
+	*count += len(buf)
+	for j, msg := range buf {
+		if j > 0 && !t.lessThan(buf[j-1].key, msg.key) {
+			return fmt.Errorf("btree: unordered buffer: %v >= %v", buf[j-1].key, msg.key)
+		}
+		if (minKey != nil && !t.greaterThan(msg.key, *minKey)) || (maxKey != nil && !t.lessThan(msg.key, *maxKey)) {
+			return fmt.Errorf("btree: buffered key %v is outside its node", msg.key)
+		}
+		if _, found := t.findIndex(n, msg.key); found {
+			return fmt.Errorf("btree: buffered key %v is also a key of its node", msg.key)
+		}
+	}
+	for i, child := range n.children {
+		childMin, childMax := minKey, maxKey
+		if i > 0 {
+			childMin = &n.items[i-1].key
+		}
+		if i < len(n.items) {
+			childMax = &n.items[i].key
+		}
+		if err := b.verifyBuffers(child, childMin, childMax, count); err != nil {
+			return err
+		}
+	}
+	return nil
+}
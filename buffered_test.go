@@ -0,0 +1,191 @@
+package btree
+
+import (
+	"fmt"
+	"maps"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func newBufferedTree(degree, bufferSize int) *BufferedBTree[int, int] {
+	opts := OptionsWithDegree(degree, intLess)
+	opts.BufferSize = bufferSize
+	return NewBuffered[int, int](opts)
+}
+
+// assertBufferedMatches 检查 b 的 Get、Ascend、AscendRange 都与 model 一致，不触发 Flush
+func assertBufferedMatches(t *testing.T, b *BufferedBTree[int, int], model map[int]int, keyRange int) {
+	t.Helper()
+	if err := b.Verify(); err != nil {
+		t.Fatalf("Verify() = %v", err)
+	}
+	for k := -1; k <= keyRange; k++ {
+		v, ok := b.Get(k)
+		want, wantOK := model[k]
+		if ok != wantOK || v != want {
+			t.Fatalf("Get(%d) = (%d,%v), want (%d,%v)", k, v, ok, want, wantOK)
+		}
+	}
+
+	keys := slices.Sorted(maps.Keys(model))
+	var got []int
+	b.Ascend(func(k, v int) bool {
+		if v != model[k] {
+			t.Fatalf("Ascend: value of %d = %d, want %d", k, v, model[k])
+		}
+		got = append(got, k)
+		return true
+	})
+	if !slices.Equal(got, keys) {
+		t.Fatalf("Ascend keys = %v, want %v", got, keys)
+	}
+
+	lo, hi := keyRange/4, keyRange/2
+	var want []int
+	for _, k := range keys {
+		if k >= lo && k < hi {
+			want = append(want, k)
+		}
+	}
+	got = got[:0]
+	b.AscendRange(lo, hi, func(k, v int) bool {
+		got = append(got, k)
+		return true
+	})
+	if !slices.Equal(got, want) {
+		t.Fatalf("AscendRange(%d,%d) = %v, want %v", lo, hi, got, want)
+	}
+}
+
+func TestBufferedRandomAgainstMap(t *testing.T) {
+	for _, tc := range []struct{ degree, bufferSize int }{{2, 2}, {2, 8}, {3, 5}, {4, 0}} {
+		b := newBufferedTree(tc.degree, tc.bufferSize)
+		model := map[int]int{}
+		rng := rand.New(rand.NewSource(int64(tc.degree*100 + tc.bufferSize)))
+		const keyRange = 400
+		sawPending := false
+		for i := 0; i < 4000; i++ {
+			k := rng.Intn(keyRange)
+			if rng.Intn(3) == 0 {
+				b.Delete(k)
+				delete(model, k)
+			} else {
+				b.Set(k, i)
+				model[k] = i
+			}
+			sawPending = sawPending || b.Pending() > 0
+			if i%250 == 0 {
+				assertBufferedMatches(t, b, model, keyRange)
+			}
+		}
+		if !sawPending {
+			t.Fatalf("degree %d, buffer %d: messages were never buffered", tc.degree, tc.bufferSize)
+		}
+		assertBufferedMatches(t, b, model, keyRange)
+
+		// Len 先 Flush：消息全部落地，墓碑被清理
+		if n := b.Len(); n != len(model) {
+			t.Fatalf("degree %d, buffer %d: Len() = %d, want %d", tc.degree, tc.bufferSize, n, len(model))
+		}
+		if b.Pending() != 0 || b.tree.Tombstones() != 0 {
+			t.Fatalf("Len() left %d pending messages and %d tombstones", b.Pending(), b.tree.Tombstones())
+		}
+		assertBufferedMatches(t, b, model, keyRange)
+	}
+}
+
+func TestBufferedDeleteAllThenReuse(t *testing.T) {
+	b := newBufferedTree(3, 4)
+	for i := 0; i < 500; i++ {
+		b.Set(i, i)
+	}
+	for i := 0; i < 500; i++ {
+		b.Delete(i)
+	}
+	if n := b.Len(); n != 0 {
+		t.Fatalf("Len() = %d after deleting all keys, want 0", n)
+	}
+	if err := b.Verify(); err != nil {
+		t.Fatalf("Verify() = %v", err)
+	}
+
+	b.Delete(7) // key 不存在，落地后什么也不做
+	if _, ok := b.Get(7); ok {
+		t.Fatalf("Get(7) found a deleted key")
+	}
+	b.Set(7, 70)
+	if v, ok := b.Get(7); !ok || v != 70 {
+		t.Fatalf("Get(7) = (%d,%v), want (70,true)", v, ok)
+	}
+	b.Clear()
+	if b.Len() != 0 || b.Pending() != 0 {
+		t.Fatalf("Clear() left Len %d, Pending %d", b.Len(), b.Pending())
+	}
+}
+
+// 删除留下的墓碑落到叶子后被丢掉，叶子可能远低于下限；消息还在缓冲区中时结构也必须合法
+func TestBufferedVerifyWhilePending(t *testing.T) {
+	b := newBufferedTree(3, 4)
+	for i := 0; i < 500; i++ {
+		b.Set(i, i)
+	}
+	for i := 0; i < 500; i++ {
+		b.Delete(i)
+		if err := b.Verify(); err != nil {
+			t.Fatalf("after Delete(%d) with %d pending: %v", i, b.Pending(), err)
+		}
+	}
+
+	var empty *BufferedBTree[int, int]
+	empty.Set(1, 1)
+	empty.Delete(1)
+	if empty.Len() != 0 {
+		t.Fatalf("nil tree Len() = %d", empty.Len())
+	}
+}
+
+func TestBufferedAscendStopsEarly(t *testing.T) {
+	b := newBufferedTree(2, 16)
+	for i := 0; i < 100; i++ {
+		b.Set(i, i)
+	}
+	var got []int
+	b.Ascend(func(k, v int) bool {
+		got = append(got, k)
+		return len(got) < 5
+	})
+	if !slices.Equal(got, []int{0, 1, 2, 3, 4}) {
+		t.Fatalf("Ascend stopped at %v", got)
+	}
+}
+
+// BenchmarkBufferedInsert 比较随机 key 插入的吞吐：BTree 的默认自顶向下 Set 与 BufferedBTree，
+// 后者包括最后一次 Flush 的代价
+func BenchmarkBufferedInsert(b *testing.B) {
+	const n = 1 << 20
+	keys := rand.New(rand.NewSource(1)).Perm(n)
+	b.Run("BTree", func(b *testing.B) {
+		for b.Loop() {
+			tree := NewWithOptions[int, int](OptionsWithDegree(32, intLess))
+			for _, k := range keys {
+				tree.Set(k, k)
+			}
+		}
+		b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*n), "ns/insert")
+	})
+	for _, size := range []int{64, 256, 1024} {
+		b.Run(fmt.Sprintf("Buffered/buffer=%d", size), func(b *testing.B) {
+			for b.Loop() {
+				opts := OptionsWithDegree(32, intLess)
+				opts.BufferSize = size
+				tree := NewBuffered[int, int](opts)
+				for _, k := range keys {
+					tree.Set(k, k)
+				}
+				tree.Flush()
+			}
+			b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*n), "ns/insert")
+		})
+	}
+}
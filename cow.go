@@ -0,0 +1,54 @@
+package btree
+
+// Clone 以 O(1) 的代价返回树的一个副本。
+// 副本与原树共享所有节点，之后任何一方修改某个共享节点时才复制它（copy-on-write），
+// 因此两棵树可以各自独立地读写。
+// Clone 会更换原树的所有权标记，不能与原树上的其他操作并发进行。
+func (t *BTree[K, V]) Clone() *BTree[K, V] {
+	if t == nil {
+		return nil
+	}
+	// 两边都换用新标记，现有节点从此不属于任何一方，谁先写谁复制
+	t.owner = new(owner)
+	return t.fork()
+}
+
+// fork 返回与 t 共享所有节点的副本，只有副本换用新标记。
+// 调用方必须保证之后不再修改 t，否则 t 仍会原地修改与副本共享的节点。
+func (t *BTree[K, V]) fork() *BTree[K, V] {
+	clone := *t
+	clone.owner = new(owner)
+	clone.spine = nil // 不与原树共用底层数组
+	clone.rightmost = nil
+	return &clone
+}
+
+// mutable 返回可以原地修改的 n：n 属于本树时直接返回，否则复制一份。
+// 复制只涉及 n 自身，孩子仍然共享。
+func (t *BTree[K, V]) mutable(n *node[K, V]) *node[K, V] {
+	if n.owner == t.owner {
+		return n
+	}
+	c := t.newNode(n.isLeaf)
+	c.items = append(c.items, n.items...)
+	if !n.isLeaf {
+		c.children = append(c.children, n.children...)
+	}
+	if n.ext != nil {
+		c.extension().agg = n.ext.agg
+		if dead := n.ext.dead; dead != nil {
+			copy(c.syncDead(), dead)
+		}
+		if buf := n.ext.buffer; len(buf) > 0 {
+			c.ext.buffer = append(c.ext.buffer, buf...)
+		}
+	}
+	return c
+}
+
+// mutableChild 保证 parent.children[i] 可以原地修改并返回它，parent 必须已经属于本树。
+func (t *BTree[K, V]) mutableChild(parent *node[K, V], i int) *node[K, V] {
+	c := t.mutable(parent.children[i])
+	parent.children[i] = c
+	return c
+}
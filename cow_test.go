@@ -0,0 +1,182 @@
+package btree
+
+import (
+	"fmt"
+	"maps"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// sharedNodes 统计 a 与 b 共享的节点个数
+func sharedNodes[K any, V any](a, b *node[K, V]) int {
+	seen := make(map[*node[K, V]]bool)
+	var mark func(n *node[K, V])
+	mark = func(n *node[K, V]) {
+		if n == nil {
+			return
+		}
+		seen[n] = true
+		for _, c := range n.children {
+			mark(c)
+		}
+	}
+	mark(a)
+	count := 0
+	var walk func(n *node[K, V])
+	walk = func(n *node[K, V]) {
+		if n == nil {
+			return
+		}
+		if seen[n] {
+			count++
+		}
+		for _, c := range n.children {
+			walk(c)
+		}
+	}
+	walk(b)
+	return count
+}
+
+func TestCloneSharesUntilWrite(t *testing.T) {
+	keys := make([]int, 1000)
+	for i := range keys {
+		keys[i] = i
+	}
+	tree := buildTree(3, keys...)
+	nodes := tree.Stats().Nodes
+
+	clone := tree.Clone()
+	if clone.root != tree.root || sharedNodes(tree.root, clone.root) != nodes {
+		t.Fatalf("Clone should share all %d nodes", nodes)
+	}
+
+	// 一次写入只复制从根到叶子的路径（最小的 key 一定在最左边的叶子里）
+	clone.Set(0, -1)
+	height := tree.Stats().Height
+	if got := sharedNodes(tree.root, clone.root); got != nodes-height {
+		t.Fatalf("after one Set the trees share %d nodes, want %d", got, nodes-height)
+	}
+	if v, _ := tree.Get(0); v != 0 {
+		t.Fatalf("original Get(0) = %d after writing the clone, want 0", v)
+	}
+	if v, _ := clone.Get(0); v != -1 {
+		t.Fatalf("clone Get(0) = %d, want -1", v)
+	}
+}
+
+// DeleteIf/Compact 只复制有元素被删除的子树
+func TestDeleteIfCopiesOnlyTouchedSubtrees(t *testing.T) {
+	keys := make([]int, 1000)
+	for i := range keys {
+		keys[i] = i
+	}
+	tree := buildTree(3, keys...)
+	nodes := tree.Stats().Nodes
+
+	clone := tree.Clone()
+	if n := clone.DeleteIf(func(k, v int) bool { return false }); n != 0 {
+		t.Fatalf("DeleteIf with no match = %d", n)
+	}
+	clone.Compact() // 没有墓碑
+	if got := sharedNodes(tree.root, clone.root); got != nodes {
+		t.Fatalf("DeleteIf without a match left %d of %d nodes shared", got, nodes)
+	}
+
+	// 删除一个叶子中的 key：最多复制从根到该叶子的路径，外加修复下溢时借位的兄弟
+	leaf := leftmostLeaf(clone.root)
+	height := tree.Stats().Height
+	clone.DeleteIf(func(k, v int) bool { return k == leaf.items[0].key })
+	if got := sharedNodes(tree.root, clone.root); got < nodes-2*height {
+		t.Fatalf("deleting one key left %d of %d nodes shared, want at least %d", got, nodes, nodes-2*height)
+	}
+	assertVerify(t, tree)
+	assertVerify(t, clone)
+	assertKeys(t, tree, keys)
+	assertKeys(t, clone, keys[1:])
+}
+
+func TestCloneIndependentMutation(t *testing.T) {
+	configs := []struct {
+		name     string
+		strategy Strategy
+		lazy     bool
+	}{
+		{"Preemptive", Preemptive, false},
+		{"BottomUp", BottomUp, false},
+		{"BStar", BStar, false},
+		{"Lazy", Preemptive, true},
+	}
+	for _, cfg := range configs {
+		t.Run(cfg.name, func(t *testing.T) {
+			r := rand.New(rand.NewSource(1))
+			opts := OptionsWithDegree(2, intLess)
+			opts.Strategy = cfg.strategy
+			opts.LazyDelete = cfg.lazy
+			opts.CompactThreshold = 0.3
+			root := NewWithMonoid[int, int](opts, sumMonoid)
+
+			// trees 中每一棵都是某个更早版本的 Clone，models 是对应的期望内容
+			trees := []*BTree[int, int]{root}
+			models := []map[int]int{{}}
+			for step := 0; step < 6000; step++ {
+				j := r.Intn(len(trees))
+				tree, model := trees[j], models[j]
+				switch k := r.Intn(300); {
+				case step%200 == 0:
+					trees = append(trees, tree.Clone())
+					models = append(models, maps.Clone(model))
+				case r.Intn(3) == 0:
+					tree.Delete(k)
+					delete(model, k)
+				case r.Intn(20) == 0:
+					n := tree.DeleteIf(func(k, v int) bool { return k%7 == 0 })
+					want := 0
+					for k := range model {
+						if k%7 == 0 {
+							delete(model, k)
+							want++
+						}
+					}
+					if n != want {
+						t.Fatalf("step %d tree %d: DeleteIf = %d, want %d", step, j, n, want)
+					}
+				default:
+					tree.Set(k, step)
+					model[k] = step
+				}
+			}
+
+			for j, tree := range trees {
+				assertVerify(t, tree)
+				want := slices.Sorted(maps.Keys(models[j]))
+				assertKeys(t, tree, want)
+				for _, k := range want {
+					if v, _ := tree.Get(k); v != models[j][k] {
+						t.Fatalf("tree %d: Get(%d) = %d, want %d", j, k, v, models[j][k])
+					}
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkClone(b *testing.B) {
+	for _, size := range []int{1000, 100000} {
+		keys := rand.New(rand.NewSource(1)).Perm(size)
+		tree := buildTree(32, keys...)
+		b.Run(fmt.Sprintf("size=%d/Clone", size), func(b *testing.B) {
+			for b.Loop() {
+				tree.Clone()
+			}
+		})
+		b.Run(fmt.Sprintf("size=%d/CloneAndSet", size), func(b *testing.B) {
+			i := 0
+			for b.Loop() {
+				tree.Clone().Set(keys[i%size], i)
+				i++
+			}
+		})
+	}
+}
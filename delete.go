@@ -9,110 +9,101 @@ func (t *BTree[K, V]) Delete(key K) (old V, deleted bool) {
 		return zero, false
 	}
 
-	old, deleted = t.deleteFromNode(t.root, key)
+	if t.options.LazyDelete {
+		return t.markDeleted(key)
+	}
+
+	old, deleted = t.remove(key)
 	if !deleted {
 		var zero V
 		return zero, false
 	}
 
 	t.size--
+	return old, true
+}
+
+// remove 从树中物理删除 key，不维护 size 和 tombstones，由调用方负责计数。
+func (t *BTree[K, V]) remove(key K) (old V, deleted bool) {
+	t.endAppends()
+	t.root = t.mutable(t.root)
+	if t.options.Strategy != Preemptive {
+		old, deleted = t.deleteBottomUp(t.root, key)
+	} else {
+		old, deleted = t.deleteFromNode(t.root, key)
+	}
+	if !deleted {
+		return old, false
+	}
 
 	// 根节点缩高逻辑：
 	// 1. 如果根是内部节点且没有 key，但有一个 child，那么提升 child 为新的根
 	// 2. 如果根是叶子且 key 数为 0，则整棵树为空，root = nil
-	if t.root != nil && len(t.root.items) == 0 {
-		if t.root.isLeaf {
-			t.root = nil
-		} else if len(t.root.children) == 1 {
-			t.root = t.root.children[0]
-		}
-	}
+	t.shrinkRoot()
 
 	return old, true
 }
 
 // deleteFromNode 在以 n 为根的子树中删除 key。
 // 返回：old, deleted 表示是否删除成功以及被删除的旧值。
+// 逐层下沉，经过的节点记录在 path 中，结束后自底向上重新计算聚合值。
 func (t *BTree[K, V]) deleteFromNode(n *node[K, V], key K) (old V, deleted bool) {
-	// 在当前结点内查找 key（或其应在位置）
-	i, found := t.findIndex(n, key)
+	path := t.spine[:0]
+	for {
+		path = append(path, n)
+		// 在当前结点内查找 key（或其应在位置）
+		i, found := t.findIndex(n, key)
 
-	if found {
-		// Case 1 & Case 2: key 在当前节点中
 		if n.isLeaf {
-			return t.deleteFromLeaf(n, i)
+			// Case 1：key 在叶子中直接删除；
+			// 到叶子还没找到，说明整棵子树都没有这个 key
+			if found {
+				old, deleted = t.deleteFromLeaf(n, i)
+			}
+			break
 		}
-		return t.deleteFromInternal(n, i)
-	}
 
-	// key 不在当前节点
-	if n.isLeaf {
-		// 到叶子还没找到，说明整棵子树都没有这个 key
-		var zero V
-		return zero, false
+		if found {
+			// Case 2：key 在内部节点中。合并之后 key 下沉到孩子里，继续在孩子中删除
+			old = n.items[i].value
+			if n, path = t.deleteFromInternal(n, i, path); n == nil {
+				deleted = true
+				break
+			}
+		} else {
+			// Case 3：key 应当落在 children[i] 对应的区间，沿着子树继续下沉
+			n = t.deleteFromChild(n, i)
+		}
 	}
-
-	// 需要沿着子树继续下沉：
-	// key 应当落在 children[i] 对应的区间
-	return t.deleteFromChild(n, i, key)
+	t.spine = t.refreshPath(path)
+	return old, deleted
 }
 
 // Case 1：key 在叶子结点中，直接删除
 func (t *BTree[K, V]) deleteFromLeaf(n *node[K, V], idx int) (old V, deleted bool) {
-	old = n.items[idx].value
-
-	// 删除 n.items[idx]：经典切片删除
-	copy(n.items[idx:], n.items[idx+1:])
-	n.items = n.items[:len(n.items)-1]
-
-	return old, true
+	// 删除 n.items[idx]：原地左移，并清空末尾
+	it, _ := n.removeItem(idx)
+	return it.value, true
 }
 
-// Case 2：key 在内部结点中，使用前驱 / 后继 / 合并策略
-func (t *BTree[K, V]) deleteFromInternal(n *node[K, V], idx int) (old V, deleted bool) {
+// Case 2：key 在内部结点中，使用前驱 / 后继 / 合并策略。
+// 用前驱或后继顶替时一趟就删完，返回 nil；合并时返回合并后的孩子，key 在其中，由调用方继续删除。
+// 经过的节点追加到 path 中并返回。
+func (t *BTree[K, V]) deleteFromInternal(n *node[K, V], idx int, path []*node[K, V]) (*node[K, V], []*node[K, V]) {
 	degree := t.options.Degree
 
-	// 记录当前要删除的 key 和旧值
-	targetKey := n.items[idx].key
-	old = n.items[idx].value
-
-	leftChild := n.children[idx]
-	rightChild := n.children[idx+1]
-
-	// Case 2A：左子树至少有 degree 个 key，用前驱替换
-	if len(leftChild.items) >= degree {
-		// 找左子树中的最大 key（前驱）
-		predNode := leftChild
-		for !predNode.isLeaf {
-			predNode = predNode.children[len(predNode.children)-1]
-		}
-		predItem := predNode.items[len(predNode.items)-1]
-
-		// 用前驱覆盖当前节点的 items[idx]
-		n.items[idx] = predItem
-
-		// 然后在左子树中删除前驱 key
-		_, deleted = t.deleteFromNode(leftChild, predItem.key)
-
-		return old, deleted
+	// Case 2A：左子树至少有 degree 个 key，摘下左子树中的最大 key（前驱）覆盖 n.items[idx]
+	if len(n.children[idx].items) >= degree {
+		it, dead, path := t.deleteMax(t.mutableChild(n, idx), path)
+		n.setItem(idx, it, dead)
+		return nil, path
 	}
 
-	// Case 2B：右子树至少有 degree 个 key，用后继替换
-	if len(rightChild.items) >= degree {
-		// 找右子树中的最小 key（后继）
-		succNode := rightChild
-		for !succNode.isLeaf {
-			succNode = succNode.children[0]
-		}
-		succItem := succNode.items[0]
-
-		// 用后继覆盖当前节点的 items[idx]
-		n.items[idx] = succItem
-
-		// 然后在右子树中删除后继 key
-		_, deleted = t.deleteFromNode(rightChild, succItem.key)
-
-		return old, deleted
+	// Case 2B：右子树至少有 degree 个 key，摘下右子树中的最小 key（后继）覆盖 n.items[idx]
+	if len(n.children[idx+1].items) >= degree {
+		it, dead, path := t.deleteMin(t.mutableChild(n, idx+1), path)
+		n.setItem(idx, it, dead)
+		return nil, path
 	}
 
 	// Case 2C：左右子树都只有 degree-1 个 key，需要合并
@@ -120,22 +111,46 @@ func (t *BTree[K, V]) deleteFromInternal(n *node[K, V], idx int) (old V, deleted
 	// 合并后：
 	// - 原来的 n.items[idx] 已经下沉到 leftChild 里面
 	// - parent.items[idx] 被删掉
-	// - children[idx] 仍指向 merge 后的那个大节点（就是 leftChild 本身）
+	// - children[idx] 仍指向 merge 后的那个大节点（mergeChildren 可能复制了 leftChild）
 	//
-	// 现在这棵 merged 子树里一定包含 targetKey，
-	// 所以在 merged 子树里递归删除 targetKey 即可。
-	return t.deleteFromNode(leftChild, targetKey)
+	// 现在这棵 merged 子树里一定包含 key，
+	// 所以在 merged 子树里继续删除 key 即可。
+	return n.children[idx], path
 }
 
-// Case 3：key 不在当前节点，需要沿某个子节点继续下沉。
+// deleteMax 删除并返回以 n 为根的子树中的最大元素，以及它是否是墓碑。
+// 沿最右路径下沉，与 deleteFromChild 一样预先补齐要进入的孩子，但不做任何 key 比较；
+// n 必须已经至少有 degree 个 key。经过的节点追加到 path 中并返回。
+func (t *BTree[K, V]) deleteMax(n *node[K, V], path []*node[K, V]) (item[K, V], bool, []*node[K, V]) {
+	for !n.isLeaf {
+		path = append(path, n)
+		n = t.deleteFromChild(n, len(n.children)-1)
+	}
+	path = append(path, n)
+	it, dead := n.removeItem(len(n.items) - 1)
+	return it, dead, path
+}
+
+// deleteMin 与 deleteMax 对称，沿最左路径删除并返回最小元素
+func (t *BTree[K, V]) deleteMin(n *node[K, V], path []*node[K, V]) (item[K, V], bool, []*node[K, V]) {
+	for !n.isLeaf {
+		path = append(path, n)
+		n = t.deleteFromChild(n, 0)
+	}
+	path = append(path, n)
+	it, dead := n.removeItem(0)
+	return it, dead, path
+}
+
+// Case 3：key 不在当前节点，需要沿某个子节点继续下沉，返回修补后要下沉的子节点。
 // 在下沉前要保证该子节点至少有 degree 个 key（不然删一下就会 < degree-1）。
-func (t *BTree[K, V]) deleteFromChild(parent *node[K, V], childIndex int, key K) (old V, deleted bool) {
+func (t *BTree[K, V]) deleteFromChild(parent *node[K, V], childIndex int) *node[K, V] {
 	degree := t.options.Degree
 
-	child := parent.children[childIndex]
+	child := t.mutableChild(parent, childIndex)
 
-	// 如果 child 的 key 数已经是最小值 degree-1，则下沉前需要修补
-	if len(child.items) == degree-1 {
+	// 如果 child 的 key 数已经是最小值 degree-1，则下沉前需要修补。
+	if len(child.items) < degree {
 		// 优先尝试从左兄弟借
 		if childIndex > 0 {
 			leftSibling := parent.children[childIndex-1]
@@ -173,22 +188,20 @@ func (t *BTree[K, V]) deleteFromChild(parent *node[K, V], childIndex int, key K)
 		}
 	}
 
-	// 至此 child 至少有 degree 个 key，可以安全递归
-	return t.deleteFromNode(child, key)
+	// 至此 child 至少有 degree 个 key（最右路径上的节点至少 2 个），可以安全下沉
+	return child
 }
 
 // mergeChildren 将 parent 的 children[idx] 和 children[idx+1] 以及中间的 items[idx]
 // 合并为一个节点，保存在 children[idx] 中。
+// right 只被读取，不需要复制。
 func (t *BTree[K, V]) mergeChildren(parent *node[K, V], idx int) {
-	left := parent.children[idx]
+	left := t.mutableChild(parent, idx)
 	right := parent.children[idx+1]
 
-	// 中间的 key 下沉到左子节点
-	midItem := parent.items[idx]
-
-	// 合并 items：left.items + midItem + right.items
-	left.items = append(left.items, midItem)
-	left.items = append(left.items, right.items...)
+	// 合并 items：left.items + 中间的 key + right.items，中间的 key 下沉到左子节点
+	left.appendItems(parent, idx, idx+1)
+	left.appendItems(right, 0, len(right.items))
 
 	// 合并 children（如果不是叶子）
 	if !left.isLeaf {
@@ -196,58 +209,57 @@ func (t *BTree[K, V]) mergeChildren(parent *node[K, V], idx int) {
 	}
 
 	// 从 parent 中移除 items[idx] 和 children[idx+1]
-	copy(parent.items[idx:], parent.items[idx+1:])
-	parent.items = parent.items[:len(parent.items)-1]
+	parent.removeItem(idx)
+	parent.children = removeAt(parent.children, idx+1)
 
-	copy(parent.children[idx+1:], parent.children[idx+2:])
-	parent.children = parent.children[:len(parent.children)-1]
+	t.refresh(left)
+	t.freeNode(right)
 }
 
 // borrowFromLeft 从左兄弟借一个 key 给 parent.children[idx]。
 func (t *BTree[K, V]) borrowFromLeft(parent *node[K, V], idx int) {
-	child := parent.children[idx]
-	leftSibling := parent.children[idx-1]
+	child := t.mutableChild(parent, idx)
+	leftSibling := t.mutableChild(parent, idx-1)
 
 	// 左兄弟最后一个 key 上移到父节点
 	// 父节点的 items[idx-1] 下移到 child 的最前面
-	// 1）child.items 前面空出一个位置
-	child.items = append(child.items, item[K, V]{}) // append 占位
-	copy(child.items[1:], child.items[:len(child.items)-1])
-	child.items[0] = parent.items[idx-1]
+	// 1）child.items 原地后移，最前面放入父节点的 key
+	child.insertItem(0, parent.items[idx-1], parent.isDead(idx-1))
 
 	// 2）父节点更新 items[idx-1]
-	parent.items[idx-1] = leftSibling.items[len(leftSibling.items)-1]
-	leftSibling.items = leftSibling.items[:len(leftSibling.items)-1]
+	it, dead := leftSibling.removeItem(len(leftSibling.items) - 1)
+	parent.setItem(idx-1, it, dead)
 
 	// 3）如果有 children，同样移动一个 child 指针
 	if !child.isLeaf {
-		// child.children = append(child.children, (*node[K, V])(nil))
-		// copy(child.children[1:], child.children[:len(child.children)-1])
-		child.children = append([]*node[K, V]{nil}, child.children...) // prepend nil
-		child.children[0] = leftSibling.children[len(leftSibling.children)-1]
-		leftSibling.children[len(leftSibling.children)-1] = nil // clear element to let GC do its job
-		leftSibling.children = leftSibling.children[:len(leftSibling.children)-1]
+		lastChild := len(leftSibling.children) - 1
+		child.children = insertAt(child.children, 0, leftSibling.children[lastChild])
+		leftSibling.children = removeAt(leftSibling.children, lastChild)
 	}
+
+	t.refresh(child)
+	t.refresh(leftSibling)
 }
 
 // borrowFromRight 从右兄弟借一个 key 给 parent.children[idx]。
 func (t *BTree[K, V]) borrowFromRight(parent *node[K, V], idx int) {
-	child := parent.children[idx]
-	rightSibling := parent.children[idx+1]
+	child := t.mutableChild(parent, idx)
+	rightSibling := t.mutableChild(parent, idx+1)
 
 	// 父节点的 items[idx] 下移到 child 的末尾
 	// 右兄弟的第一个 key 上移到父节点
-	child.items = append(child.items, parent.items[idx])
-	parent.items[idx] = rightSibling.items[0]
+	child.appendItems(parent, idx, idx+1)
 
-	// 右兄弟 items 左移
-	copy(rightSibling.items[0:], rightSibling.items[1:])
-	rightSibling.items = rightSibling.items[:len(rightSibling.items)-1]
+	// 右兄弟 items 原地左移
+	it, dead := rightSibling.removeItem(0)
+	parent.setItem(idx, it, dead)
 
 	// children 同理
 	if !child.isLeaf {
 		child.children = append(child.children, rightSibling.children[0])
-		copy(rightSibling.children[0:], rightSibling.children[1:])
-		rightSibling.children = rightSibling.children[:len(rightSibling.children)-1]
+		rightSibling.children = removeAt(rightSibling.children, 0)
 	}
+
+	t.refresh(child)
+	t.refresh(rightSibling)
 }
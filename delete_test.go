@@ -1,6 +1,8 @@
 package btree
 
 import (
+	"fmt"
+	"math/rand"
 	"slices"
 	"testing"
 )
@@ -265,3 +267,98 @@ func TestDeleteBulk(t *testing.T) {
 	assertVerify(t, tree)
 	assertKeys(t, tree, want)
 }
+
+// countingTree 建一棵 Less 会计数的树，返回树和比较次数计数器
+func countingTree(degree int, keys []int) (*BTree[int, int], *int) {
+	calls := new(int)
+	tree := NewWithOptions[int, int](OptionsWithDegree(degree, func(a, b int) int {
+		*calls++
+		return intLess(a, b)
+	}))
+	for _, k := range keys {
+		tree.Set(k, k)
+	}
+	return tree, calls
+}
+
+// internalKeys 返回树中所有位于内部节点的 key
+func internalKeys(n *node[int, int]) []int {
+	if n == nil || n.isLeaf {
+		return nil
+	}
+	keys := nodeKeys(n)
+	for _, c := range n.children {
+		keys = append(keys, internalKeys(c)...)
+	}
+	return keys
+}
+
+func TestDeleteInternalSinglePass(t *testing.T) {
+	keys := rand.New(rand.NewSource(1)).Perm(1000)
+	tree, calls := countingTree(3, keys)
+	single, two := 0, 0
+	for _, k := range internalKeys(tree.root)[:100] {
+		twoPass := tree.Clone()
+		*calls = 0
+		if _, ok := twoPass.recDelete(k); !ok {
+			t.Fatalf("recDelete(%d) did not find the key", k)
+		}
+		before := *calls
+
+		*calls = 0
+		if _, ok := tree.Delete(k); !ok {
+			t.Fatalf("Delete(%d) did not find the key", k)
+		}
+		// 一路合并到叶子时两种做法都不需要找前驱，比较次数相同
+		if *calls > before {
+			t.Fatalf("Delete(%d) used %d comparisons, two-pass delete %d", k, *calls, before)
+		}
+		single, two = single+*calls, two+before
+		if a, b := dumpNode(tree.root), dumpNode(twoPass.root); a != b {
+			t.Fatalf("Delete(%d) left a different tree than the two-pass delete", k)
+		}
+	}
+	if single >= two {
+		t.Fatalf("deleting internal keys used %d comparisons, two-pass delete %d", single, two)
+	}
+	assertVerify(t, tree)
+}
+
+// BenchmarkDeleteComparisons 比较单趟删除（Delete）与先找前驱再按 key 删除的两趟删除（recDelete）
+// 每次删除调用 Less 的次数，分别删除内部节点中的 key 和随机的 key
+func BenchmarkDeleteComparisons(b *testing.B) {
+	const n = 1 << 16
+	keys := rand.New(rand.NewSource(1)).Perm(n)
+	for _, degree := range []int{3, 32} {
+		base, calls := countingTree(degree, keys)
+		targets := map[string][]int{
+			"internal": internalKeys(base.root),
+			"random":   keys[:n/2],
+		}
+		for _, workload := range []string{"internal", "random"} {
+			for _, impl := range []string{"two-pass", "single-pass"} {
+				name := fmt.Sprintf("degree=%d/%s/%s", degree, workload, impl)
+				b.Run(name, func(b *testing.B) {
+					del := targets[workload]
+					var tree *BTree[int, int]
+					i, total := 0, 0
+					for b.Loop() {
+						if i%len(del) == 0 {
+							tree = base.Clone() // 删完一轮后从原树重新开始
+						}
+						k := del[i%len(del)]
+						*calls = 0
+						if impl == "two-pass" {
+							tree.recDelete(k)
+						} else {
+							tree.Delete(k)
+						}
+						total += *calls
+						i++
+					}
+					b.ReportMetric(float64(total)/float64(i), "cmps/op")
+				})
+			}
+		}
+	}
+}
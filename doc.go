@@ -15,8 +15,11 @@ package btree
 
 // B-Tree 不变式：
 // 每个非根节点的 key 数量在 [degree-1, 2*degree-1] 之间；
+// （BStar 策略下限提高到 2/3 满，即 floor(2*(2*degree-1)/3)）
+// （FastAppend 连续追加期间（looseRight）最右路径的下限放宽为 1，endAppends 恢复正常下限）
 //
 // 根节点的 key 数量不超过 2*degree-1（可以为 0 或 >=1）；
+// （BStar 策略下根的上限放宽为两倍的下限，分裂后两个孩子恰好 2/3 满）
 //
 // 对于内部节点：len(children) == len(items)+1；
 //
@@ -36,7 +39,7 @@ package btree
 
 // 数量是 m，节点内部保证严格递增；
 
-// 对非根节点，m 必须在 [degree-1, 2*degree-1] 内。
+// 对非根节点，m 必须在 [degree-1, 2*degree-1] 内（连续追加期间最右路径除外，见上）。
 
 // children：
 
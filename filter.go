@@ -0,0 +1,236 @@
+package btree
+
+// DeleteIf 删除所有满足 pred 的元素，返回删除的个数。
+// pred 对每个元素恰好调用一次，调用期间不能修改树。
+//
+// 实现分两步：先在每个叶子内原地压缩，再自底向上用借位/合并修复下溢的节点；
+// 内部节点中命中的 key 数量很少（约为总数的 1/degree），修复完成后再逐个删除。
+// 如果删除的元素超过一半，则直接用剩余元素重建整棵树，比逐个修复更省。
+func (t *BTree[K, V]) DeleteIf(pred func(k K, v V) bool) int {
+	if t == nil || t.root == nil {
+		return 0
+	}
+	// 墓碑对调用方不可见，不交给 pred，但可以顺带物理删除
+	return t.removeIf(func(it *item[K, V], dead bool) bool {
+		return dead || pred(it.key, it.value)
+	})
+}
+
+// RetainIf 只保留满足 pred 的元素，返回删除的个数。
+func (t *BTree[K, V]) RetainIf(pred func(k K, v V) bool) int {
+	return t.DeleteIf(func(k K, v V) bool {
+		return !pred(k, v)
+	})
+}
+
+// removeIf 物理删除所有满足 match 的元素，返回其中存活元素的个数。
+// match 的第二个参数表示元素是否是墓碑。
+func (t *BTree[K, V]) removeIf(match func(it *item[K, V], dead bool) bool) int {
+	var internal []K // 内部节点中命中的 key，按升序收集
+	live, dead := 0, 0
+	t.endAppends()
+	t.root, _ = t.compactNode(t.root, func(it *item[K, V], isDead bool) bool {
+		if !match(it, isDead) {
+			return false
+		}
+		if isDead {
+			dead++
+		} else {
+			live++
+		}
+		return true
+	}, &internal)
+	if live+dead == 0 {
+		return 0
+	}
+
+	if (live+dead)*2 > t.size+t.tombstones {
+		t.rebuildWithout(internal)
+		return live
+	}
+
+	// B* 树中暂时无法修复的节点要等父节点借位或合并之后再来一遍。每一遍没完成时都至少调用一次 fixChild
+	// （最上层卡住的节点由它的父节点修复），而每次 fixChild 要么合并减少节点数，要么不减少节点数
+	// 但把一个下溢的孩子补足、兄弟不低于下限，下溢节点数减少；(节点数, 下溢节点数) 严格递减，循环必然结束。
+	for {
+		fixes := 0
+		if t.repairNode(t.root, &fixes) {
+			break
+		}
+		if fixes == 0 {
+			panic("btree: DeleteIf repair made no progress")
+		}
+	}
+	t.size -= live
+	t.tombstones -= dead
+	t.shrinkRoot()
+	for _, k := range internal {
+		t.remove(k)
+	}
+	return live
+}
+
+// compactNode 中序遍历以 n 为根的子树：叶子内原地删除命中的元素，
+// 内部节点命中的 key 追加到 internal。
+// 子树中有元素被删除时才用 mutable 取得可以修改的 n，Clone 之后共享的节点不会被无谓地复制；
+// 返回处理后的 n 以及它是否被修改过。压缩后的叶子可能下溢，由 repairNode 负责修复。
+func (t *BTree[K, V]) compactNode(n *node[K, V], match func(it *item[K, V], dead bool) bool, internal *[]K) (*node[K, V], bool) {
+	if n.isLeaf {
+		first := 0
+		for first < len(n.items) && !match(&n.items[first], n.isDead(first)) {
+			first++
+		}
+		if first == len(n.items) {
+			return n, false
+		}
+		n = t.mutable(n)
+		dead := n.deadFlags()
+		kept := first
+		for i := first + 1; i < len(n.items); i++ {
+			if !match(&n.items[i], dead != nil && dead[i]) {
+				n.items[kept] = n.items[i]
+				if dead != nil {
+					dead[kept] = dead[i]
+				}
+				kept++
+			}
+		}
+		n.truncateItems(kept) // 让被删元素尽快被 GC 回收
+		t.refresh(n)
+		return n, true
+	}
+
+	changed := false
+	for i := 0; i < len(n.children); i++ {
+		if child, ok := t.compactNode(n.children[i], match, internal); ok {
+			if !changed {
+				n = t.mutable(n)
+				changed = true
+			}
+			n.children[i] = child
+		}
+		// 内部节点命中的 key 留到修复之后由 remove 删除，这里不修改 n
+		if i < len(n.items) && match(&n.items[i], n.isDead(i)) {
+			*internal = append(*internal, n.items[i].key)
+		}
+	}
+	if changed {
+		t.refresh(n)
+	}
+	return n, changed
+}
+
+// repairNode 自底向上修复 n 的子树中所有下溢（key 数少于 minItems）的节点，n 自身是否下溢由它的父节点负责。
+// BStar 策略下由 fixChild 借位或 3 并 2；非根节点只剩两个孩子、两者的 key 又不够分成两个节点时，
+// 合并成一个会超过上限，只能先跳过：此时 n 自身一定下溢，父节点给它借来或合并进更多孩子之后，
+// 下一遍就能修复。有节点被跳过时返回 false；fixes 累加调用 fixChild 的次数。
+func (t *BTree[K, V]) repairNode(n *node[K, V], fixes *int) bool {
+	if n.isLeaf {
+		return true
+	}
+	done := true
+	for _, child := range n.children {
+		done = t.repairNode(child, fixes) && done
+	}
+
+	minItems := t.minItems()
+	for i := 0; i < len(n.children); {
+		if len(n.children[i].items) >= minItems {
+			i++
+			continue
+		}
+		if len(n.children) == 1 {
+			// 只剩一个孩子，n 本身也下溢了，交给上层处理
+			return done
+		}
+		if t.options.Strategy == BStar && len(n.children) == 2 && n != t.root &&
+			len(n.children[0].items)+len(n.children[1].items) < 2*minItems {
+			return false
+		}
+		i = t.fixChild(n, i)
+		*fixes++
+	}
+	return done
+}
+
+// fixChild 修复 parent.children[idx] 的下溢：先尽量从兄弟借，借不够再合并；
+// 孩子没有下溢时什么也不做。
+// 与 deleteFromChild 不同，这里的孩子可能远低于 degree-1（甚至为 0），
+// 因此借位会重复进行。返回下一个需要检查的孩子索引。
+func (t *BTree[K, V]) fixChild(parent *node[K, V], idx int) int {
+	minItems := t.minItems()
+	if len(parent.children[idx].items) >= minItems {
+		return idx + 1
+	}
+	child := t.mutableChild(parent, idx)
+
+	if idx > 0 {
+		for len(child.items) < minItems && len(parent.children[idx-1].items) > minItems {
+			t.borrowFromLeft(parent, idx)
+		}
+	}
+	if idx+1 < len(parent.children) {
+		for len(child.items) < minItems && len(parent.children[idx+1].items) > minItems {
+			t.borrowFromRight(parent, idx)
+		}
+	}
+	if len(child.items) >= minItems {
+		return idx + 1
+	}
+
+	if t.options.Strategy == BStar {
+		return t.mergeStar(parent, idx)
+	}
+
+	// 兄弟都只剩 degree-1 个 key（或本身也下溢），合并后总数不超过 2*degree-2
+	if idx+1 < len(parent.children) {
+		t.mergeChildren(parent, idx)
+		return idx // 合并后的节点仍可能下溢，重新检查
+	}
+	t.mergeChildren(parent, idx-1)
+	return idx - 1
+}
+
+// shrinkRoot 去掉没有 key 的根：内部节点提升唯一的孩子，空叶子则清空整棵树。
+func (t *BTree[K, V]) shrinkRoot() {
+	for t.root != nil && len(t.root.items) == 0 {
+		old := t.root
+		if old.isLeaf {
+			t.root = nil
+		} else {
+			t.root = old.children[0]
+		}
+		t.freeNode(old)
+	}
+}
+
+// rebuildWithout 用树中剩余的存活元素重建整棵树，跳过 internal 中列出的 key。
+// 此时叶子已经压缩过，结构可能下溢，但中序遍历仍然正确。
+// Ascend 不会访问墓碑，重建后的树中也不再有墓碑。
+// 重建不开 FastAppend：90/10 分裂留下的 looseRight 不会带回 t，最右路径的下溢就再也补不上了。
+func (t *BTree[K, V]) rebuildWithout(internal []K) {
+	options := t.options
+	options.FastAppend = false
+	fresh := NewWithOptions[K, V](options)
+	fresh.monoid = t.monoid
+	fresh.owner = t.owner
+	fresh.search = t.search
+	fresh.freelist = t.freelist
+	j := 0
+	t.Ascend(func(k K, v V) bool {
+		// internal 中可能有墓碑的 key，它们不会出现在遍历中
+		for j < len(internal) && t.lessThan(internal[j], k) {
+			j++
+		}
+		if j < len(internal) && t.equal(k, internal[j]) {
+			j++
+			return true
+		}
+		fresh.Set(k, v)
+		return true
+	})
+	t.freeTree(t.root)
+	t.root = fresh.root
+	t.size = fresh.size
+	t.tombstones = 0
+}
@@ -0,0 +1,221 @@
+package btree
+
+import (
+	"maps"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestDeleteIfOnNilAndEmpty(t *testing.T) {
+	var nilTree *BTree[int, int]
+	if n := nilTree.DeleteIf(func(k, v int) bool { return true }); n != 0 {
+		t.Fatalf("DeleteIf on nil tree = %d, want 0", n)
+	}
+
+	empty := NewWithOptions[int, int](DefaultOptions(intLess))
+	if n := empty.DeleteIf(func(k, v int) bool { return true }); n != 0 {
+		t.Fatalf("DeleteIf on empty tree = %d, want 0", n)
+	}
+}
+
+func TestDeleteIfNoMatch(t *testing.T) {
+	keys := make([]int, 100)
+	for i := range keys {
+		keys[i] = i
+	}
+	tree := buildTree(3, keys...)
+
+	if n := tree.DeleteIf(func(k, v int) bool { return k < 0 }); n != 0 {
+		t.Fatalf("DeleteIf with no match = %d, want 0", n)
+	}
+	assertVerify(t, tree)
+	assertKeys(t, tree, keys)
+}
+
+func TestDeleteIfCallsPredOncePerItem(t *testing.T) {
+	const N = 300
+	keys := make([]int, N)
+	for i := range keys {
+		keys[i] = i
+	}
+	tree := buildTree(2, keys...)
+
+	seen := make(map[int]int)
+	tree.DeleteIf(func(k, v int) bool {
+		seen[k]++
+		return k%3 == 0
+	})
+
+	if len(seen) != N {
+		t.Fatalf("pred saw %d distinct keys, want %d", len(seen), N)
+	}
+	for k, c := range seen {
+		if c != 1 {
+			t.Fatalf("pred called %d times for key %d, want 1", c, k)
+		}
+	}
+}
+
+func TestDeleteIfRepairsUnderflow(t *testing.T) {
+	for _, degree := range []int{2, 3, 4, 32} {
+		const N = 1000
+		keys := make([]int, N)
+		for i := range keys {
+			keys[i] = i
+		}
+		tree := buildTree(degree, keys...)
+
+		// 删除约三分之一，走原地修复路径
+		n := tree.DeleteIf(func(k, v int) bool { return k%3 == 0 })
+
+		var want []int
+		for _, k := range keys {
+			if k%3 != 0 {
+				want = append(want, k)
+			}
+		}
+		if n != N-len(want) {
+			t.Fatalf("degree %d: DeleteIf = %d, want %d", degree, n, N-len(want))
+		}
+		if tree.Len() != len(want) {
+			t.Fatalf("degree %d: Len() = %d, want %d", degree, tree.Len(), len(want))
+		}
+		assertVerify(t, tree)
+		assertKeys(t, tree, want)
+	}
+}
+
+// leftmostLeaf 返回 n 的子树中最左边的叶子
+func leftmostLeaf(n *node[int, int]) *node[int, int] {
+	for !n.isLeaf {
+		n = n.children[0]
+	}
+	return n
+}
+
+// B* 树删除不到一半时原地修复：节点对象被复用（重建会换掉所有节点），借位和 3 并 2 之后仍然至少 2/3 满
+func TestDeleteIfRepairsBStarInPlace(t *testing.T) {
+	for _, degree := range []int{2, 3, 4, 8} {
+		tree := buildTreeWithStrategy(degree, BStar)
+		for i := 0; i < 3000; i++ {
+			tree.Set(i, i)
+		}
+		leaf := leftmostLeaf(tree.root)
+		if n := tree.DeleteIf(func(k, v int) bool { return k%7 == 0 }); n != 429 {
+			t.Fatalf("degree %d: DeleteIf = %d, want 429", degree, n)
+		}
+		if leftmostLeaf(tree.root) != leaf {
+			t.Fatalf("degree %d: sparse DeleteIf rebuilt the tree", degree)
+		}
+		assertVerify(t, tree)
+	}
+
+	// 随机的区间和零散删除，可能走到无法就地修复、退回重建的情况
+	rng := rand.New(rand.NewSource(7))
+	for round := 0; round < 300; round++ {
+		degree := 2 + rng.Intn(4)
+		opts := OptionsWithDegree(degree, intLess)
+		opts.Strategy = BStar
+		tree := NewWithMonoid[int, int](opts, sumMonoid)
+		model := map[int]int{}
+		for i := 0; i < 500; i++ {
+			k := rng.Intn(1000)
+			tree.Set(k, i)
+			model[k] = i
+		}
+		lo, width, sparse := rng.Intn(1000), rng.Intn(300), rng.Intn(8)
+		removed := tree.DeleteIf(func(k, v int) bool {
+			return (k >= lo && k < lo+width) || k%11 < sparse
+		})
+		before := len(model)
+		maps.DeleteFunc(model, func(k, v int) bool {
+			return (k >= lo && k < lo+width) || k%11 < sparse
+		})
+		if removed != before-len(model) {
+			t.Fatalf("round %d: DeleteIf = %d, want %d", round, removed, before-len(model))
+		}
+		assertVerify(t, tree)
+		assertKeys(t, tree, slices.Sorted(maps.Keys(model)))
+	}
+}
+
+func TestDeleteIfClusteredRange(t *testing.T) {
+	// 一整段连续的 key 被删除，会清空若干相邻叶子
+	const N = 500
+	keys := make([]int, N)
+	for i := range keys {
+		keys[i] = i
+	}
+	tree := buildTree(3, keys...)
+
+	n := tree.DeleteIf(func(k, v int) bool { return k >= 100 && k < 300 })
+	if n != 200 {
+		t.Fatalf("DeleteIf = %d, want 200", n)
+	}
+
+	want := append(slices.Clone(keys[:100]), keys[300:]...)
+	assertVerify(t, tree)
+	assertKeys(t, tree, want)
+}
+
+func TestDeleteIfRebuildsWhenMostRemoved(t *testing.T) {
+	const N = 1000
+	keys := make([]int, N)
+	for i := range keys {
+		keys[i] = i
+	}
+	tree := buildTree(3, keys...)
+
+	n := tree.DeleteIf(func(k, v int) bool { return k%10 != 0 })
+	if n != N-N/10 {
+		t.Fatalf("DeleteIf = %d, want %d", n, N-N/10)
+	}
+
+	var want []int
+	for i := 0; i < N; i += 10 {
+		want = append(want, i)
+	}
+	if tree.Len() != len(want) {
+		t.Fatalf("Len() = %d, want %d", tree.Len(), len(want))
+	}
+	assertVerify(t, tree)
+	assertKeys(t, tree, want)
+}
+
+func TestDeleteIfAll(t *testing.T) {
+	tree := buildTree(2, 5, 1, 4, 2, 3, 9, 8, 7, 6)
+
+	if n := tree.DeleteIf(func(k, v int) bool { return true }); n != 9 {
+		t.Fatalf("DeleteIf(all) = %d, want 9", n)
+	}
+	if tree.Len() != 0 || tree.root != nil {
+		t.Fatalf("tree should be empty, got Len()=%d root=%+v", tree.Len(), tree.root)
+	}
+
+	// 清空后树仍然可用
+	tree.Set(1, 1)
+	assertVerify(t, tree)
+	assertKeys(t, tree, []int{1})
+}
+
+func TestRetainIf(t *testing.T) {
+	const N = 400
+	keys := make([]int, N)
+	for i := range keys {
+		keys[i] = i
+	}
+	tree := buildTree(4, keys...)
+
+	n := tree.RetainIf(func(k, v int) bool { return k%4 == 1 })
+	if n != N-N/4 {
+		t.Fatalf("RetainIf = %d, want %d", n, N-N/4)
+	}
+
+	var want []int
+	for i := 1; i < N; i += 4 {
+		want = append(want, i)
+	}
+	assertVerify(t, tree)
+	assertKeys(t, tree, want)
+}
@@ -0,0 +1,116 @@
+package btree
+
+import "sync"
+
+// DefaultFreeListSize 是 NewFreeList 的 size 为 0 时缓存的节点上限
+const DefaultFreeListSize = 32
+
+// FreeList 缓存树中不再使用的节点，供之后分裂、复制节点时复用，减少频繁增删带来的 GC 压力。
+// 被回收的节点清空内容但保留 items/children 的容量。
+//
+// 通过 NewWithFreeList 可以在多棵 K、V 相同的树之间共享一个 FreeList，
+// 它是并发安全的：不同 goroutine 中的不同树可以同时使用它（同一棵树本身仍然不是并发安全的）。
+type FreeList[K any, V any] struct {
+	mu    sync.Mutex
+	nodes []*node[K, V]
+	size  int
+}
+
+// NewFreeList 创建最多缓存 size 个节点的 FreeList，size 为 0 时使用 DefaultFreeListSize
+func NewFreeList[K any, V any](size int) *FreeList[K, V] {
+	if size == 0 {
+		size = DefaultFreeListSize
+	}
+	return &FreeList[K, V]{
+		nodes: make([]*node[K, V], 0, size),
+		size:  size,
+	}
+}
+
+// Len 返回当前缓存的节点个数
+func (f *FreeList[K, V]) Len() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.nodes)
+}
+
+func (f *FreeList[K, V]) get() *node[K, V] {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	last := len(f.nodes) - 1
+	if last < 0 {
+		return nil
+	}
+	n := f.nodes[last]
+	f.nodes[last] = nil
+	f.nodes = f.nodes[:last]
+	return n
+}
+
+// put 缓存 n，已满时返回 false
+func (f *FreeList[K, V]) put(n *node[K, V]) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.nodes) >= f.size {
+		return false
+	}
+	f.nodes = append(f.nodes, n)
+	return true
+}
+
+// NewWithFreeList 创建一棵从 f 中取节点、把不再使用的节点还给 f 的树。
+// 树在分裂/复制节点时从 f 中取节点，合并/清空时把节点还回去；f 为 nil 时等同于 NewWithOptions。
+func NewWithFreeList[K any, V any](options Options[K], f *FreeList[K, V]) *BTree[K, V] {
+	t := NewWithOptions[K, V](options)
+	t.freelist = f
+	return t
+}
+
+// allocNode 优先从 FreeList 中取节点，没有时新建
+func (t *BTree[K, V]) allocNode(isLeaf bool) *node[K, V] {
+	if t.freelist != nil {
+		if n := t.freelist.get(); n != nil {
+			n.isLeaf = isLeaf
+			n.owner = t.owner
+			switch {
+			case isLeaf:
+				n.children = nil
+			case cap(n.children) == 0:
+				n.children = make([]*node[K, V], 0, t.nodeCapacity()+1)
+			}
+			return n
+		}
+	}
+	// 增强树的每个节点都要缓存聚合值，ext 与节点一起分配
+	return newNode[K, V](isLeaf, t.nodeCapacity(), t.owner, t.monoid != nil)
+}
+
+// freeNode 把不再使用的 n 还给 FreeList。
+// 只回收属于本树的节点：Clone 之后共享的节点可能仍被其他树引用。
+func (t *BTree[K, V]) freeNode(n *node[K, V]) bool {
+	if t.freelist == nil || n.owner != t.owner {
+		return false
+	}
+	clear(n.items)
+	n.items = n.items[:0]
+	clear(n.children)
+	n.children = n.children[:0]
+	if n.ext != nil {
+		*n.ext = nodeExt[K, V]{}
+	}
+	return t.freelist.put(n)
+}
+
+// freeTree 把以 n 为根的子树中属于本树的节点还给 FreeList，FreeList 满时提前停止。
+// 孩子在父节点之前回收，父节点被清空后就不能再访问孩子了。
+func (t *BTree[K, V]) freeTree(n *node[K, V]) bool {
+	if t.freelist == nil || n == nil {
+		return false
+	}
+	for _, child := range n.children {
+		if !t.freeTree(child) {
+			return false
+		}
+	}
+	return n.owner != t.owner || t.freeNode(n)
+}
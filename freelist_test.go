@@ -0,0 +1,178 @@
+package btree
+
+import (
+	"maps"
+	"math/rand"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestFreeListReusesNodes(t *testing.T) {
+	f := NewFreeList[int, int](8)
+	tree := NewWithFreeList[int, int](OptionsWithDegree(2, intLess), f)
+	for i := 0; i < 100; i++ {
+		tree.Set(i, i)
+	}
+
+	tree.Clear()
+	if f.Len() != 8 {
+		t.Fatalf("FreeList.Len() = %d after Clear, want the cap 8", f.Len())
+	}
+	for i := 0; i < 10; i++ {
+		tree.Set(i, i)
+	}
+	if f.Len() >= 8 {
+		t.Fatalf("FreeList.Len() = %d, want nodes drawn from it", f.Len())
+	}
+	assertVerify(t, tree)
+	assertKeys(t, tree, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9})
+}
+
+// DeleteIf 删除过半时重建整棵树，新树的节点也要从 FreeList 中取
+func TestFreeListReusedByDeleteIfRebuild(t *testing.T) {
+	f := NewFreeList[int, int](64)
+	tree := NewWithFreeList[int, int](OptionsWithDegree(2, intLess), f)
+	for i := 0; i < 200; i++ {
+		tree.Set(i, i)
+	}
+	scratch := NewWithFreeList[int, int](OptionsWithDegree(2, intLess), f)
+	for i := 0; i < 200; i++ {
+		scratch.Set(i, i)
+	}
+	scratch.Clear()
+	pooled := map[*node[int, int]]bool{}
+	for _, n := range f.nodes {
+		pooled[n] = true
+	}
+
+	tree.DeleteIf(func(k, _ int) bool { return k%4 != 0 })
+	assertVerify(t, tree)
+	reused := 0
+	var walk func(n *node[int, int])
+	walk = func(n *node[int, int]) {
+		if pooled[n] {
+			reused++
+		}
+		for _, c := range n.children {
+			walk(c)
+		}
+	}
+	walk(tree.root)
+	if reused == 0 {
+		t.Fatalf("rebuilt tree took no nodes from the FreeList")
+	}
+}
+
+func TestNewWithNilFreeList(t *testing.T) {
+	tree := NewWithFreeList[int, int](OptionsWithDegree(2, intLess), nil)
+	for i := 0; i < 100; i++ {
+		tree.Set(i, i)
+	}
+	tree.Clear()
+	tree.Set(1, 1)
+	assertVerify(t, tree)
+	assertKeys(t, tree, []int{1})
+}
+
+// 共享 FreeList 的多棵树（包括 Clone 出来的副本）交替增删，节点被回收复用后内容仍然正确
+func TestFreeListSharedAcrossTrees(t *testing.T) {
+	for _, s := range []Strategy{Preemptive, BottomUp, BStar} {
+		r := rand.New(rand.NewSource(int64(s)))
+		f := NewFreeList[int, int](16)
+		opts := OptionsWithDegree(2, intLess)
+		opts.Strategy = s
+
+		withMonoid := NewWithMonoid[int, int](opts, sumMonoid)
+		withMonoid.freelist = f
+		trees := []*BTree[int, int]{withMonoid, NewWithFreeList[int, int](opts, f)}
+		models := []map[int]int{{}, {}}
+		for step := 0; step < 8000; step++ {
+			j := r.Intn(len(trees))
+			k := r.Intn(300)
+			switch {
+			case step%1000 == 999:
+				trees = append(trees, trees[j].Clone())
+				models = append(models, maps.Clone(models[j]))
+			case step%700 == 699:
+				trees[j].Clear()
+				clear(models[j])
+			case r.Intn(2) == 0:
+				trees[j].Delete(k)
+				delete(models[j], k)
+			default:
+				trees[j].Set(k, step)
+				models[j][k] = step
+			}
+		}
+
+		for j, tree := range trees {
+			assertVerify(t, tree)
+			assertKeys(t, tree, slices.Sorted(maps.Keys(models[j])))
+			for k, want := range models[j] {
+				if v, _ := tree.Get(k); v != want {
+					t.Fatalf("strategy %d tree %d: Get(%d) = %d, want %d", s, j, k, v, want)
+				}
+			}
+		}
+	}
+}
+
+// 不同 goroutine 中的树共享同一个 FreeList，用 -race 运行
+func TestFreeListConcurrentTrees(t *testing.T) {
+	f := NewFreeList[int, int](64)
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tree := NewWithFreeList[int, int](OptionsWithDegree(2, intLess), f)
+			r := rand.New(rand.NewSource(int64(g)))
+			for i := 0; i < 5000; i++ {
+				if k := r.Intn(500); r.Intn(2) == 0 {
+					tree.Delete(k)
+				} else {
+					tree.Set(k, i)
+				}
+			}
+			if err := tree.Verify(); err != nil {
+				t.Errorf("goroutine %d: Verify() = %v", g, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// BenchmarkFreeList 在持续插入删除的负载下比较有无 FreeList 的内存分配
+func BenchmarkFreeList(b *testing.B) {
+	const N = 10000
+	keys := rand.New(rand.NewSource(1)).Perm(2 * N)
+	for _, withFreeList := range []bool{false, true} {
+		name := "without"
+		if withFreeList {
+			name = "with"
+		}
+		b.Run(name, func(b *testing.B) {
+			var f *FreeList[int, int]
+			if withFreeList {
+				f = NewFreeList[int, int](0)
+			}
+			tree := NewWithFreeList[int, int](OptionsWithDegree(4, intLess), f)
+			for _, k := range keys[:N] {
+				tree.Set(k, k)
+			}
+			b.ReportAllocs()
+			var c mallocCounter
+			c.resume()
+			i := 0
+			for b.Loop() {
+				// 删掉最旧的 key、插入一个新的 key，树的大小保持不变
+				tree.Delete(keys[i%(2*N)])
+				tree.Set(keys[(i+N)%(2*N)], i)
+				i++
+			}
+			c.pause()
+			b.ReportMetric(float64(c.total)/float64(i), "mallocs/op")
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package btree
+
+import "math/bits"
+
+// FrozenTree 是 BTree 的只读快照，key 按 Eytzinger（BFS）顺序连续存放：
+// keys[i] 的左右孩子是 keys[2i] 和 keys[2i+1]，keys[0] 不使用。
+// 查找是一条没有指针跳转的隐式二叉树路径，前几层集中在数组开头，总是留在缓存中；
+// 每层只有一次比较，分支结果只决定下标，没有 BTree 节点内查找的循环。
+// value 放在与 keys 平行的数组中，查找时不会把 value 读进缓存。
+type FrozenTree[K any, V any] struct {
+	less   LessFunc[K]
+	keys   []K // 长度为 Len()+1
+	values []V
+}
+
+// Freeze 返回树当前存活元素的只读快照，之后对树的修改不会影响它
+func (t *BTree[K, V]) Freeze() *FrozenTree[K, V] {
+	if t == nil {
+		return &FrozenTree[K, V]{}
+	}
+	return freeze(t.options.Less, t.size, t.Ascend)
+}
+
+// freeze 用升序遍历 ascend 给出的 size 个元素建立快照
+func freeze[K any, V any](less LessFunc[K], size int, ascend func(fn func(k K, v V) bool)) *FrozenTree[K, V] {
+	f := &FrozenTree[K, V]{
+		less:   less,
+		keys:   make([]K, size+1),
+		values: make([]V, size+1),
+	}
+	// 中序遍历隐式树的顺序就是 key 的升序，边遍历原树边沿后继填入
+	i := f.first()
+	ascend(func(k K, v V) bool {
+		f.keys[i], f.values[i] = k, v
+		i = f.next(i)
+		return true
+	})
+	return f
+}
+
+func (f *FrozenTree[K, V]) Len() int {
+	if f == nil || len(f.keys) == 0 {
+		return 0
+	}
+	return len(f.keys) - 1
+}
+
+// first 返回最小 key 的下标（一路向左），树为空时返回 0
+func (f *FrozenTree[K, V]) first() int {
+	i := 0
+	for j := 1; j < len(f.keys); j *= 2 {
+		i = j
+	}
+	return i
+}
+
+// last 返回最大 key 的下标（一路向右），树为空时返回 0
+func (f *FrozenTree[K, V]) last() int {
+	i := 0
+	for j := 1; j < len(f.keys); j = 2*j + 1 {
+		i = j
+	}
+	return i
+}
+
+// next 返回 i 的中序后继，没有后继时返回 0：
+// 有右子树时取右子树的最左节点，否则向上退过所有作为右孩子的祖先，再退一步
+func (f *FrozenTree[K, V]) next(i int) int {
+	if 2*i+1 < len(f.keys) {
+		i = 2*i + 1
+		for 2*i < len(f.keys) {
+			i *= 2
+		}
+		return i
+	}
+	return i >> (bits.TrailingZeros(^uint(i)) + 1)
+}
+
+// prev 返回 i 的中序前驱，没有前驱时返回 0
+func (f *FrozenTree[K, V]) prev(i int) int {
+	if 2*i < len(f.keys) {
+		i *= 2
+		for 2*i+1 < len(f.keys) {
+			i = 2*i + 1
+		}
+		return i
+	}
+	return i >> (bits.TrailingZeros(uint(i)) + 1)
+}
+
+// ceiling 返回第一个 >= key 的下标，不存在时返回 0。
+// 下沉到底后，最后一次向左走的节点就是答案：去掉路径末尾连续的向右（二进制末尾的 1）和一次向左。
+func (f *FrozenTree[K, V]) ceiling(key K) int {
+	i := 1
+	for i < len(f.keys) {
+		if f.less(f.keys[i], key) < 0 {
+			i = 2*i + 1
+		} else {
+			i = 2 * i
+		}
+	}
+	return i >> (bits.TrailingZeros(^uint(i)) + 1)
+}
+
+// floor 返回最后一个 <= key 的下标，不存在时返回 0，与 ceiling 对称
+func (f *FrozenTree[K, V]) floor(key K) int {
+	i := 1
+	for i < len(f.keys) {
+		if f.less(f.keys[i], key) <= 0 {
+			i = 2*i + 1
+		} else {
+			i = 2 * i
+		}
+	}
+	return i >> (bits.TrailingZeros(uint(i)) + 1)
+}
+
+// Get
+func (f *FrozenTree[K, V]) Get(key K) (V, bool) {
+	var zero V
+	if f.Len() == 0 {
+		return zero, false
+	}
+	i := f.ceiling(key)
+	if i == 0 || f.less(f.keys[i], key) != 0 {
+		return zero, false
+	}
+	return f.values[i], true
+}
+
+// Floor 返回最大的 <= key 的元素，不存在时 ok 为 false
+func (f *FrozenTree[K, V]) Floor(key K) (k K, v V, ok bool) {
+	if f.Len() == 0 {
+		return k, v, false
+	}
+	if i := f.floor(key); i != 0 {
+		return f.keys[i], f.values[i], true
+	}
+	return k, v, false
+}
+
+// Ceiling 返回最小的 >= key 的元素，不存在时 ok 为 false
+func (f *FrozenTree[K, V]) Ceiling(key K) (k K, v V, ok bool) {
+	if f.Len() == 0 {
+		return k, v, false
+	}
+	if i := f.ceiling(key); i != 0 {
+		return f.keys[i], f.values[i], true
+	}
+	return k, v, false
+}
+
+// Ascend 按升序遍历，fn 返回 false 时停止
+func (f *FrozenTree[K, V]) Ascend(fn func(k K, v V) bool) {
+	if f.Len() == 0 {
+		return
+	}
+	for i := f.first(); i != 0; i = f.next(i) {
+		if !fn(f.keys[i], f.values[i]) {
+			return
+		}
+	}
+}
+
+// AscendRange 按升序遍历 [greaterOrEqual, lessThan) 区间内的元素，fn 返回 false 时停止
+func (f *FrozenTree[K, V]) AscendRange(greaterOrEqual, lessThan K, fn func(k K, v V) bool) {
+	if f.Len() == 0 {
+		return
+	}
+	for i := f.ceiling(greaterOrEqual); i != 0 && f.less(f.keys[i], lessThan) < 0; i = f.next(i) {
+		if !fn(f.keys[i], f.values[i]) {
+			return
+		}
+	}
+}
+
+// Descend 按降序遍历，fn 返回 false 时停止
+func (f *FrozenTree[K, V]) Descend(fn func(k K, v V) bool) {
+	if f.Len() == 0 {
+		return
+	}
+	for i := f.last(); i != 0; i = f.prev(i) {
+		if !fn(f.keys[i], f.values[i]) {
+			return
+		}
+	}
+}
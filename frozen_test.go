@@ -0,0 +1,128 @@
+package btree
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestFrozenMatchesTree(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 3, 7, 8, 100, 1000} {
+		tree := buildLazyTree(3, 0)
+		rng := rand.New(rand.NewSource(int64(n)))
+		var keys []int
+		for _, k := range rng.Perm(4 * n)[:n] {
+			tree.Set(2*k, k) // 只用偶数，奇数用来查询不存在的 key
+			keys = append(keys, 2*k)
+		}
+		// 墓碑不应出现在快照中
+		for _, k := range keys[:n/4] {
+			tree.Delete(k)
+		}
+		keys = keys[n/4:]
+		slices.Sort(keys)
+
+		f := tree.Freeze()
+		if f.Len() != len(keys) {
+			t.Fatalf("n=%d: Len() = %d, want %d", n, f.Len(), len(keys))
+		}
+		var got []int
+		f.Ascend(func(k, v int) bool {
+			if v != k/2 {
+				t.Fatalf("n=%d: value of %d = %d", n, k, v)
+			}
+			got = append(got, k)
+			return true
+		})
+		if !slices.Equal(got, keys) {
+			t.Fatalf("n=%d: Ascend = %v, want %v", n, got, keys)
+		}
+		got = got[:0]
+		f.Descend(func(k, v int) bool {
+			got = append(got, k)
+			return true
+		})
+		slices.Reverse(got)
+		if !slices.Equal(got, keys) {
+			t.Fatalf("n=%d: Descend = %v, want %v", n, got, keys)
+		}
+
+		for q := -1; q <= 8*n+1; q++ {
+			i, found := slices.BinarySearch(keys, q)
+			if v, ok := f.Get(q); ok != found || (found && v != q/2) {
+				t.Fatalf("n=%d: Get(%d) = (%d,%v), want found=%v", n, q, v, ok, found)
+			}
+			if k, _, ok := f.Ceiling(q); ok != (i < len(keys)) || (ok && k != keys[i]) {
+				t.Fatalf("n=%d: Ceiling(%d) = (%d,%v)", n, q, k, ok)
+			}
+			fi := i - 1
+			if found {
+				fi = i
+			}
+			if k, _, ok := f.Floor(q); ok != (fi >= 0) || (ok && k != keys[fi]) {
+				t.Fatalf("n=%d: Floor(%d) = (%d,%v)", n, q, k, ok)
+			}
+		}
+
+		lo, hi := 2*n, 5*n
+		var want []int
+		for _, k := range keys {
+			if k >= lo && k < hi {
+				want = append(want, k)
+			}
+		}
+		got = got[:0]
+		f.AscendRange(lo, hi, func(k, v int) bool {
+			got = append(got, k)
+			return true
+		})
+		if !slices.Equal(got, want) {
+			t.Fatalf("n=%d: AscendRange(%d,%d) = %v, want %v", n, lo, hi, got, want)
+		}
+	}
+}
+
+func TestFrozenIndependentOfTree(t *testing.T) {
+	tree := buildTree(2, 1, 2, 3, 4, 5)
+	f := tree.Freeze()
+	tree.Set(6, 6)
+	tree.Delete(1)
+	if _, ok := f.Get(1); !ok {
+		t.Fatalf("Freeze() snapshot lost key 1 after it was deleted from the tree")
+	}
+	if _, ok := f.Get(6); ok || f.Len() != 5 {
+		t.Fatalf("Freeze() snapshot sees later writes: Len() = %d", f.Len())
+	}
+
+	var empty *BTree[int, int]
+	if f := empty.Freeze(); f.Len() != 0 {
+		t.Fatalf("nil tree Freeze().Len() = %d", f.Len())
+	}
+}
+
+func BenchmarkFrozenGet(b *testing.B) {
+	for _, size := range []int{1 << 10, 1 << 20} {
+		tree := NewWithOptions[int, int](DefaultOptions(intLess))
+		for _, k := range rand.New(rand.NewSource(1)).Perm(size) {
+			tree.Set(k, k)
+		}
+		f := tree.Freeze()
+		queries := rand.New(rand.NewSource(2)).Perm(size)
+
+		b.Run(fmt.Sprintf("size=%d/BTree", size), func(b *testing.B) {
+			i := 0
+			for b.Loop() {
+				tree.Get(queries[i%size])
+				i++
+			}
+		})
+		b.Run(fmt.Sprintf("size=%d/Frozen", size), func(b *testing.B) {
+			i := 0
+			for b.Loop() {
+				f.Get(queries[i%size])
+				i++
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package btree
+
+// maxHintDepth 是 PathHint 记录的层数，更深的层照常查找
+const maxHintDepth = 16
+
+// PathHint 记录上一次下沉时每一层选中的索引，SetHint/GetHint 在每层先检查这个位置，
+// 命中时最多三次比较，不命中再回退到 findIndex。零值可以直接使用。
+// 访问的 key 相互靠近（聚集、顺序）时命中率高；hint 过期只影响速度，不影响结果。
+// 同一个 PathHint 不能被多个 goroutine 同时使用。
+type PathHint struct {
+	path [maxHintDepth]int32
+	used uint16 // 第 depth 位为 1 表示 path[depth] 有效
+}
+
+// findIndexHint 与 findIndex 相同，但先尝试 hint 在第 depth 层记录的位置，并把结果写回 hint。
+// hint 为 nil 时就是 findIndex。
+func (t *BTree[K, V]) findIndexHint(n *node[K, V], key K, hint *PathHint, depth int) (int, bool) {
+	if hint == nil || depth >= maxHintDepth {
+		return t.findIndex(n, key)
+	}
+	var i int
+	var found bool
+	if hint.used&(1<<depth) == 0 {
+		i, found = t.findIndex(n, key)
+	} else {
+		i, found = t.tryHint(n, key, int(hint.path[depth]))
+	}
+	hint.path[depth] = int32(i)
+	hint.used |= 1 << depth
+	return i, found
+}
+
+// tryHint 检查 key 是否落在 items[h-1] 与 items[h+1] 之间，是则直接返回位置，否则回退到 findIndex
+func (t *BTree[K, V]) tryHint(n *node[K, V], key K, h int) (int, bool) {
+	h = min(h, len(n.items))
+	if h > 0 {
+		switch c := t.cmp(n.items[h-1].key, key); {
+		case c == 0:
+			return h - 1, true
+		case c > 0:
+			return t.findIndex(n, key)
+		}
+	}
+	for end := min(h+2, len(n.items)); h < end; h++ {
+		// 顺序访问时 key 往往恰好在上次位置的下一个，多看一个位置
+		if c := t.cmp(n.items[h].key, key); c >= 0 {
+			return h, c == 0
+		}
+	}
+	if h < len(n.items) {
+		return t.findIndex(n, key)
+	}
+	return h, false
+}
+
+// GetHint 与 Get 相同，但用 hint 加速每一层的查找，并把这次下沉的路径记录到 hint 中。
+// hint 为 nil 时等同于 Get。
+func (t *BTree[K, V]) GetHint(key K, hint *PathHint) (V, bool) {
+	var value V
+	if t == nil || t.root == nil {
+		return value, false
+	}
+	return t.get(t.root, key, hint)
+}
+
+// SetHint 与 Set 相同，但用 hint 加速每一层的查找，并把这次下沉的路径记录到 hint 中。
+// hint 为 nil 时等同于 Set。
+func (t *BTree[K, V]) SetHint(key K, value V, hint *PathHint) (old V, replaced bool) {
+	if t == nil {
+		return old, false
+	}
+	return t.set(key, value, hint)
+}
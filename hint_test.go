@@ -0,0 +1,146 @@
+package btree
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func TestHintAgainstMap(t *testing.T) {
+	for _, s := range []Strategy{Preemptive, BottomUp, BStar} {
+		for _, degree := range []int{2, 3, 8} {
+			tree := buildTreeWithStrategy(degree, s)
+			model := map[int]int{}
+			rng := rand.New(rand.NewSource(int64(degree)))
+			var hint PathHint
+			base := 0
+			for i := 0; i < 5000; i++ {
+				if i%50 == 0 {
+					base = rng.Intn(10000) // 换一个聚集的区域，hint 随之过期
+				}
+				k := base + rng.Intn(40)
+				switch rng.Intn(4) {
+				case 0, 1:
+					old, replaced := tree.SetHint(k, i, &hint)
+					if want, ok := model[k]; ok != replaced || old != want {
+						t.Fatalf("SetHint(%d) = (%d,%v), want (%d,%v)", k, old, replaced, want, ok)
+					}
+					model[k] = i
+				case 2:
+					v, ok := tree.GetHint(k, &hint)
+					if want, found := model[k]; ok != found || v != want {
+						t.Fatalf("GetHint(%d) = (%d,%v), want (%d,%v)", k, v, ok, want, found)
+					}
+				default:
+					// 不经过 hint 的删除让记录的路径失效
+					tree.Delete(k)
+					delete(model, k)
+				}
+			}
+			assertVerify(t, tree)
+			if tree.Len() != len(model) {
+				t.Fatalf("Len() = %d, want %d", tree.Len(), len(model))
+			}
+		}
+	}
+
+	var empty *BTree[int, int]
+	if _, ok := empty.GetHint(1, nil); ok {
+		t.Fatalf("nil tree GetHint found a key")
+	}
+	tree := buildTree(3, 1, 2, 3)
+	if v, ok := tree.GetHint(2, nil); !ok || v != 2 {
+		t.Fatalf("GetHint with nil hint = (%d,%v)", v, ok)
+	}
+}
+
+func TestHintSavesComparisons(t *testing.T) {
+	calls := 0
+	counting := func(a, b int) int {
+		calls++
+		return intLess(a, b)
+	}
+	tree := NewWithOptions[int, int](OptionsWithDegree(32, counting))
+	for i := 0; i < 100000; i++ {
+		tree.Set(i, i)
+	}
+
+	calls = 0
+	for i := 0; i < 100000; i++ {
+		tree.Get(i)
+	}
+	plain := calls
+
+	calls = 0
+	var hint PathHint
+	for i := 0; i < 100000; i++ {
+		tree.GetHint(i, &hint)
+	}
+	if calls >= plain*2/3 {
+		t.Fatalf("sequential GetHint used %d comparisons, Get used %d", calls, plain)
+	}
+}
+
+// hintWorkloads 返回三种访问顺序的 key：聚集（每 64 次换一个随机区域）、顺序、随机
+func hintWorkloads(n int) map[string][]int {
+	rng := rand.New(rand.NewSource(1))
+	clustered := make([]int, n)
+	base := 0
+	for i := range clustered {
+		if i%64 == 0 {
+			base = rng.Intn(n)
+		}
+		clustered[i] = base + i%64
+	}
+	sequential := make([]int, n)
+	for i := range sequential {
+		sequential[i] = i
+	}
+	return map[string][]int{
+		"clustered":  clustered,
+		"sequential": sequential,
+		"random":     rng.Perm(n),
+	}
+}
+
+func BenchmarkHint(b *testing.B) {
+	const n = 1 << 18
+	for _, name := range []string{"clustered", "sequential", "random"} {
+		keys := hintWorkloads(n)[name]
+		tree := NewWithOptions[int, int](DefaultOptions(intLess))
+		for _, k := range rand.New(rand.NewSource(2)).Perm(n + 64) {
+			tree.Set(k, k)
+		}
+
+		b.Run(fmt.Sprintf("%s/Get", name), func(b *testing.B) {
+			i := 0
+			for b.Loop() {
+				tree.Get(keys[i%n])
+				i++
+			}
+		})
+		b.Run(fmt.Sprintf("%s/GetHint", name), func(b *testing.B) {
+			var hint PathHint
+			i := 0
+			for b.Loop() {
+				tree.GetHint(keys[i%n], &hint)
+				i++
+			}
+		})
+		b.Run(fmt.Sprintf("%s/Set", name), func(b *testing.B) {
+			i := 0
+			for b.Loop() {
+				tree.Set(keys[i%n], i)
+				i++
+			}
+		})
+		b.Run(fmt.Sprintf("%s/SetHint", name), func(b *testing.B) {
+			var hint PathHint
+			i := 0
+			for b.Loop() {
+				tree.SetHint(keys[i%n], i, &hint)
+				i++
+			}
+		})
+	}
+}
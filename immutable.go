@@ -0,0 +1,60 @@
+package btree
+
+// ImmutableBTree 是持久化（不可变）的 B-Tree：Set 和 Delete 不修改当前版本，而是返回一个新版本。
+// 新版本只复制从根到被修改位置的路径上的节点（path copying），其余节点与旧版本共享，
+// 分裂、合并等再平衡逻辑与 BTree 完全相同。
+//
+// 任何版本都可以一直保留并继续读取，适合撤销栈；版本一旦创建就不会再被修改，
+// 因此多个 goroutine 可以不加锁地读取同一个版本，也可以同时从同一个版本派生新版本。
+type ImmutableBTree[K any, V any] struct {
+	tree *BTree[K, V]
+}
+
+// NewImmutable 创建一个空的 ImmutableBTree
+func NewImmutable[K any, V any](options Options[K]) *ImmutableBTree[K, V] {
+	return &ImmutableBTree[K, V]{tree: NewWithOptions[K, V](options)}
+}
+
+func (t *ImmutableBTree[K, V]) Len() int {
+	return t.tree.Len()
+}
+
+func (t *ImmutableBTree[K, V]) Get(key K) (V, bool) {
+	return t.tree.Get(key)
+}
+
+// Set 返回写入 key 之后的新版本，t 保持不变
+func (t *ImmutableBTree[K, V]) Set(key K, value V) *ImmutableBTree[K, V] {
+	next := t.tree.fork()
+	next.Set(key, value)
+	return &ImmutableBTree[K, V]{tree: next}
+}
+
+// Delete 返回删除 key 之后的新版本，t 保持不变；key 不存在时直接返回 t
+func (t *ImmutableBTree[K, V]) Delete(key K) *ImmutableBTree[K, V] {
+	next := t.tree.fork()
+	if _, deleted := next.Delete(key); !deleted {
+		return t
+	}
+	return &ImmutableBTree[K, V]{tree: next}
+}
+
+// Ascend 按升序遍历当前版本，fn 返回 false 时停止
+func (t *ImmutableBTree[K, V]) Ascend(fn func(k K, v V) bool) {
+	t.tree.Ascend(fn)
+}
+
+// AscendRange 按升序遍历当前版本中 [greaterOrEqual, lessThan) 内的元素
+func (t *ImmutableBTree[K, V]) AscendRange(greaterOrEqual, lessThan K, fn func(k K, v V) bool) {
+	t.tree.AscendRange(greaterOrEqual, lessThan, fn)
+}
+
+// Mutable 返回内容与当前版本相同的可变 BTree，之后对它的修改不影响任何版本
+func (t *ImmutableBTree[K, V]) Mutable() *BTree[K, V] {
+	return t.tree.fork()
+}
+
+// Verify 检查当前版本的 B-Tree 不变式
+func (t *ImmutableBTree[K, V]) Verify() error {
+	return t.tree.Verify()
+}
@@ -0,0 +1,118 @@
+package btree
+
+import (
+	"maps"
+	"math/rand"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestImmutableVersions(t *testing.T) {
+	for _, s := range []Strategy{Preemptive, BottomUp, BStar} {
+		r := rand.New(rand.NewSource(int64(s)))
+		opts := OptionsWithDegree(2, intLess)
+		opts.Strategy = s
+
+		// 保留每一个历史版本以及它对应的内容
+		versions := []*ImmutableBTree[int, int]{NewImmutable[int, int](opts)}
+		models := []map[int]int{{}}
+		for i := 0; i < 500; i++ {
+			// 大多数时候在最新版本上修改，偶尔从某个旧版本分叉
+			j := len(versions) - 1
+			if r.Intn(10) == 0 {
+				j = r.Intn(len(versions))
+			}
+			model := maps.Clone(models[j])
+			k := r.Intn(200)
+			var next *ImmutableBTree[int, int]
+			if r.Intn(3) == 0 {
+				next = versions[j].Delete(k)
+				delete(model, k)
+			} else {
+				next = versions[j].Set(k, i)
+				model[k] = i
+			}
+			versions = append(versions, next)
+			models = append(models, model)
+		}
+
+		for j, v := range versions {
+			if err := v.Verify(); err != nil {
+				t.Fatalf("strategy %d version %d: Verify() = %v", s, j, err)
+			}
+			if v.Len() != len(models[j]) {
+				t.Fatalf("strategy %d version %d: Len() = %d, want %d", s, j, v.Len(), len(models[j]))
+			}
+			var got []int
+			v.Ascend(func(k, val int) bool {
+				if want := models[j][k]; val != want {
+					t.Fatalf("strategy %d version %d: value of %d = %d, want %d", s, j, k, val, want)
+				}
+				got = append(got, k)
+				return true
+			})
+			if want := slices.Sorted(maps.Keys(models[j])); !slices.Equal(got, want) {
+				t.Fatalf("strategy %d version %d: keys = %v, want %v", s, j, got, want)
+			}
+			for k, want := range models[j] {
+				if val, ok := v.Get(k); !ok || val != want {
+					t.Fatalf("strategy %d version %d: Get(%d) = (%d,%v), want (%d,true)", s, j, k, val, ok, want)
+				}
+			}
+		}
+	}
+}
+
+func TestImmutableDeleteMissingReturnsSameVersion(t *testing.T) {
+	v := NewImmutable[int, int](OptionsWithDegree(2, intLess)).Set(1, 1)
+	if v.Delete(2) != v {
+		t.Fatalf("Delete of a missing key should return the same version")
+	}
+}
+
+func TestImmutableMutable(t *testing.T) {
+	v := NewImmutable[int, int](OptionsWithDegree(2, intLess))
+	for i := 0; i < 50; i++ {
+		v = v.Set(i, i)
+	}
+	m := v.Mutable()
+	for i := 0; i < 50; i += 2 {
+		m.Delete(i)
+	}
+	assertVerify(t, m)
+	if v.Len() != 50 || m.Len() != 25 {
+		t.Fatalf("version Len() = %d, mutable Len() = %d, want 50 and 25", v.Len(), m.Len())
+	}
+}
+
+// 多个 goroutine 同时读取同一个版本并从它派生新版本，用 -race 运行
+func TestImmutableConcurrentReaders(t *testing.T) {
+	base := NewImmutable[int, int](OptionsWithDegree(3, intLess))
+	for i := 0; i < 1000; i++ {
+		base = base.Set(i, i)
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v := base
+			for i := 0; i < 1000; i++ {
+				if val, ok := base.Get(i); !ok || val != i {
+					t.Errorf("Get(%d) = (%d,%v) on the shared version", i, val, ok)
+					return
+				}
+				v = v.Set(i, -g)
+			}
+			if err := v.Verify(); err != nil {
+				t.Errorf("Verify() = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if err := base.Verify(); err != nil {
+		t.Fatalf("base Verify() = %v", err)
+	}
+}
@@ -2,93 +2,126 @@ package btree
 
 // grow: 当根满时，分裂根并增加树高
 func (t *BTree[K, V]) grow() {
+	if t.options.Strategy == BStar {
+		// B* 的根更大，从正中间分开，两个孩子都恰好达到 2/3 的下限
+		t.growAt(len(t.root.items) / 2)
+	} else {
+		t.growAt(t.options.Degree - 1)
+	}
+}
+
+// growAt 以 root.items[mid] 为中间 key 分裂根，增加树高
+func (t *BTree[K, V]) growAt(mid int) {
 	oldRoot := t.root
-	newRoot := newInternalNodeWithChild(oldRoot)
+	newRoot := t.newNode(false)
+	newRoot.children = append(newRoot.children, oldRoot)
 
-	// children[0] 是原来的根，如果它是满节点，splitChild 会把它拆成两半，
-	// 中间的 key 上浮到 newRoot.items[0]
-	t.splitChild(newRoot, 0)
+	// children[0] 是原来的根，splitChildAt 把它拆成两半，中间的 key 上浮到 newRoot.items[0]
+	t.splitChildAt(newRoot, 0, mid)
+	t.refresh(newRoot)
 	t.root = newRoot
-
 }
 
-func (t *BTree[K, V]) insertNonFull(n *node[K, V], key K, value V) (old V, replaced bool) {
-	// 叶子节点：直接插入/更新
-	if n.isLeaf {
-		i, found := t.findIndex(n, key)
+// insertNonFull 把 key 插入以不满的 n 为根的子树，hint 可以为 nil（见 SetHint）。
+// 逐层下沉，经过的节点记录在 path 中，结束后自底向上重新计算聚合值。
+func (t *BTree[K, V]) insertNonFull(n *node[K, V], key K, value V, hint *PathHint) (old V, replaced bool) {
+	path := t.spine[:0]
+	for depth := 0; ; depth++ {
+		path = append(path, n)
+		i, found := t.findIndexHint(n, key, hint, depth)
 		if found {
-			old = n.items[i].value
-			n.items[i].value = value
-			return old, true
+			// 当前节点已包含 key，直接更新
+			old, replaced = t.overwrite(n, i, value)
+			break
 		}
 
-		// 在切片中间插入
-		n.items = append(n.items, item[K, V]{})
-		copy(n.items[i+1:], n.items[i:])
-		n.items[i] = item[K, V]{key: key, value: value}
+		// 叶子节点：在切片中间插入
+		if n.isLeaf {
+			t.insertItemAt(n, i, item[K, V]{key: key, value: value})
+			break
+		}
 
-		return // old = zero value, replaced = false
-	}
+		child := t.mutableChild(n, i)
+
+		// 下沉前：如果 child 是满的，先分裂它
+		if t.isFull(child) {
+			t.splitChild(n, i)
+
+			// splitChild 之后，n.items[i] 是从 child 提升上来的中间 key
+			// 它可能恰好就是要写入的 key，此时直接更新
+			// 否则判断 key 应该去左孩子还是右孩子
+			c := t.cmp(key, n.items[i].key)
+			if c == 0 {
+				old, replaced = t.overwrite(n, i, value)
+				break
+			}
+			if c > 0 {
+				i++
+			}
+		}
 
-	// 内部节点：先找到要下沉的 child
-	i, found := t.findIndex(n, key)
-	if found {
-		// 当前节点已包含 key，直接更新
-		old = n.items[i].value
-		n.items[i].value = value
-		return old, true
+		// 此时 n.children[i] 一定是不满节点，可以继续下沉
+		n = n.children[i]
 	}
+	t.spine = t.refreshPath(path)
+	return old, replaced
+}
 
-	child := n.children[i]
-
-	// 下沉前：如果 child 是满的，先分裂它
-	if t.isFull(child) {
-		t.splitChild(n, i)
+// insertItemAt 把 it 插入到 n.items[i]，后面的元素依次后移。
+func (t *BTree[K, V]) insertItemAt(n *node[K, V], i int, it item[K, V]) {
+	n.insertItem(i, it, false)
+}
 
-		// splitChild 之后，n.items[i] 是从 child 提升上来的中间 key
-		// 判断 key 应该去左孩子还是右孩子
-		if t.greaterThan(key, n.items[i].key) {
-			i++
-		}
+// overwrite 用 value 覆盖已存在的 n.items[i]。
+// 如果它是墓碑，则相当于插入了一个新元素：复活它并返回 replaced=false。
+func (t *BTree[K, V]) overwrite(n *node[K, V], i int, value V) (old V, replaced bool) {
+	it := &n.items[i]
+	if n.isDead(i) {
+		n.setDead(i, false)
+		it.value = value
+		t.tombstones--
+		return old, false
 	}
-
-	// 此时 n.children[i] 一定是不满节点，可以安全递归
-	return t.insertNonFull(n.children[i], key, value)
+	old = it.value
+	it.value = value
+	return old, true
 }
 
 // @param parent: 父节点
 // @param index: parent.children 中要被 split 的子节点索引,即插入已经满了的节点
+// BottomUp 策略下 child 可能已经溢出（2*degree 个 key），此时右半部分多分到一个 key
 func (t *BTree[K, V]) splitChild(parent *node[K, V], index int) {
-	degree := t.options.Degree
-	child := parent.children[index]
-	mid := degree - 1 // 中间节点索引
+	t.splitChildAt(parent, index, t.options.Degree-1)
+}
+
+// splitChildAt 以 child.items[mid] 为中间节点分裂 parent.children[index]
+// parent 必须已经属于本树（见 mutable），child 在这里按需复制。
+func (t *BTree[K, V]) splitChildAt(parent *node[K, V], index int, mid int) {
+	child := t.mutableChild(parent, index)
 
 	// right 节点存储 child 右半部分的 items 和 children
-	right := &node[K, V]{
-		isLeaf: child.isLeaf,
-	}
+	right := t.newNode(child.isLeaf)
 
 	// 保留中间节点
-	midItem := child.items[mid]
+	midItem, midDead := child.items[mid], child.isDead(mid)
 
-	// 把 child 右半部分的 items 移动到 right
-	right.items = append(right.items, child.items[mid+1:]...)
-	child.items = child.items[:mid] // 保留左半部分,不包括中间节点(左开右闭)
+	// 把 child 右半部分的 items 移动到 right，child 保留原数组，清空腾出的位置以便 GC 回收
+	right.appendItems(child, mid+1, len(child.items))
+	child.truncateItems(mid) // 保留左半部分,不包括中间节点(左开右闭)
 
 	// 如果 child 不是叶节点，还要移动 children
 	if !child.isLeaf {
 		right.children = append(right.children, child.children[mid+1:]...)
+		clear(child.children[mid+1:])
 		child.children = child.children[:mid+1]
 	}
 
-	// 把 child 的中间节点上浮到 parent
-	parent.items = append(parent.items, item[K, V]{})  // 扩容
-	copy(parent.items[index+1:], parent.items[index:]) // 后移
-	parent.items[index] = midItem                      // 中间节点上浮
+	// 把 child 的中间节点上浮到 parent，right 作为 parent 的新子节点插入
+	parent.insertItem(index, midItem, midDead)
+	parent.children = insertAt(parent.children, index+1, right)
 
-	// 把 right 作为 parent 的新子节点插入
-	parent.children = append(parent.children, nil) // 扩容
-	copy(parent.children[index+2:], parent.children[index+1:])
-	parent.children[index+1] = right
+	// parent 子树的内容没有变化，只有被拆开的两个节点需要重新计算聚合值
+	t.refresh(child)
+	t.refresh(right)
 
 }
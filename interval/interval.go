@@ -0,0 +1,126 @@
+// Package interval 在 btree 之上实现区间树。
+//
+// 区间按左端点（相同时按右端点）排序存放在增强 B-Tree 中，
+// 每个节点通过 btree.Monoid 缓存其子树中最大的右端点。
+// 查询与 [a, b] 重叠的区间时，左端点大于 b 即可停止，最大右端点小于 a 的子树整棵跳过。
+package interval
+
+import (
+	"cmp"
+	"fmt"
+
+	btree "github.com/ormasia/b-tree"
+)
+
+// Interval 是闭区间 [Start, End]
+type Interval[T cmp.Ordered] struct {
+	Start T
+	End   T
+}
+
+// IntervalTree 保存区间到 value 的映射，同一个区间只保存一份。
+type IntervalTree[T cmp.Ordered, V any] struct {
+	tree *btree.BTree[Interval[T], entry[T, V]]
+}
+
+// entry 是底层 B-Tree 中的 value：元素自身的右端点与 value；
+// 作为聚合值时只有 maxEnd 有意义，ok 为 false 表示空子树（单位元）。
+type entry[T cmp.Ordered, V any] struct {
+	maxEnd T
+	value  V
+	ok     bool
+}
+
+func compareIntervals[T cmp.Ordered](a, b Interval[T]) int {
+	if c := cmp.Compare(a.Start, b.Start); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.End, b.End)
+}
+
+// maxEnd 组合两个子树的聚合值，得到最大的右端点
+func maxEnd[T cmp.Ordered, V any](a, b entry[T, V]) entry[T, V] {
+	switch {
+	case !a.ok:
+		return entry[T, V]{maxEnd: b.maxEnd, ok: b.ok}
+	case !b.ok || a.maxEnd >= b.maxEnd:
+		return entry[T, V]{maxEnd: a.maxEnd, ok: true}
+	default:
+		return entry[T, V]{maxEnd: b.maxEnd, ok: true}
+	}
+}
+
+// New 创建一棵最小度为 degree 的区间树，degree 为 0 时使用 btree 的默认值。
+func New[T cmp.Ordered, V any](degree int) *IntervalTree[T, V] {
+	options := btree.OptionsWithDegree(degree, compareIntervals[T])
+	return &IntervalTree[T, V]{
+		tree: btree.NewWithMonoid[Interval[T], entry[T, V]](options, btree.Monoid[entry[T, V]]{
+			Combine: maxEnd[T, V],
+			// 聚合值只关心 maxEnd，value 总是零值，不参与比较
+			Equal: func(a, b entry[T, V]) bool {
+				return a.ok == b.ok && (!a.ok || a.maxEnd == b.maxEnd)
+			},
+		}),
+	}
+}
+
+func (t *IntervalTree[T, V]) Len() int {
+	return t.tree.Len()
+}
+
+// Insert 插入区间 [start, end]，区间已存在时覆盖其 value。end 小于 start 时 panic。
+func (t *IntervalTree[T, V]) Insert(start, end T, value V) (old V, replaced bool) {
+	if end < start {
+		panic(fmt.Sprintf("interval: end %v is before start %v", end, start))
+	}
+	prev, replaced := t.tree.Set(Interval[T]{start, end}, entry[T, V]{maxEnd: end, value: value, ok: true})
+	return prev.value, replaced
+}
+
+// Get 返回区间 [start, end] 对应的 value
+func (t *IntervalTree[T, V]) Get(start, end T) (V, bool) {
+	e, ok := t.tree.Get(Interval[T]{start, end})
+	return e.value, ok
+}
+
+// Delete 删除区间 [start, end]
+func (t *IntervalTree[T, V]) Delete(start, end T) (old V, deleted bool) {
+	e, deleted := t.tree.Delete(Interval[T]{start, end})
+	return e.value, deleted
+}
+
+// Overlapping 按左端点升序遍历所有与 [a, b] 相交的区间，fn 返回 false 时停止。
+func (t *IntervalTree[T, V]) Overlapping(a, b T, fn func(iv Interval[T], v V) bool) {
+	t.tree.AscendPruned(
+		// 子树中最大的右端点都小于 a，不可能有区间与 [a, b] 相交
+		func(agg entry[T, V]) bool {
+			return agg.ok && agg.maxEnd >= a
+		},
+		func(iv Interval[T], e entry[T, V]) bool {
+			if iv.Start > b {
+				return false // 之后的区间左端点只会更大
+			}
+			if iv.End >= a {
+				return fn(iv, e.value)
+			}
+			return true
+		},
+	)
+}
+
+// Stabbing 按左端点升序遍历所有包含点 p 的区间，fn 返回 false 时停止。
+func (t *IntervalTree[T, V]) Stabbing(p T, fn func(iv Interval[T], v V) bool) {
+	t.Overlapping(p, p, fn)
+}
+
+// Ascend 按左端点升序遍历所有区间
+func (t *IntervalTree[T, V]) Ascend(fn func(iv Interval[T], v V) bool) {
+	t.tree.Ascend(func(iv Interval[T], e entry[T, V]) bool {
+		return fn(iv, e.value)
+	})
+}
+
+// Verify 检查底层 B-Tree 的不变式以及每个节点缓存的最大右端点
+func (t *IntervalTree[T, V]) Verify() error {
+	return t.tree.Verify()
+}
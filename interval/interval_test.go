@@ -0,0 +1,120 @@
+package interval
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func collect[V any](query func(fn func(iv Interval[int], v V) bool)) []Interval[int] {
+	var got []Interval[int]
+	query(func(iv Interval[int], v V) bool {
+		got = append(got, iv)
+		return true
+	})
+	return got
+}
+
+func bruteOverlapping(model map[Interval[int]]int, a, b int) []Interval[int] {
+	var want []Interval[int]
+	for iv := range model {
+		if iv.Start <= b && iv.End >= a {
+			want = append(want, iv)
+		}
+	}
+	slices.SortFunc(want, compareIntervals[int])
+	return want
+}
+
+func TestOverlappingAndStabbing(t *testing.T) {
+	for _, degree := range []int{2, 3, 8} {
+		r := rand.New(rand.NewSource(int64(degree)))
+		tree := New[int, int](degree)
+		model := make(map[Interval[int]]int)
+
+		for i := 0; i < 3000; i++ {
+			start := r.Intn(1000)
+			iv := Interval[int]{start, start + r.Intn(50)}
+			if r.Intn(4) == 0 {
+				old, deleted := tree.Delete(iv.Start, iv.End)
+				want, ok := model[iv]
+				if deleted != ok || old != want {
+					t.Fatalf("degree %d: Delete(%v) = (%d,%v), want (%d,%v)", degree, iv, old, deleted, want, ok)
+				}
+				delete(model, iv)
+			} else {
+				old, replaced := tree.Insert(iv.Start, iv.End, i)
+				want, ok := model[iv]
+				if replaced != ok || old != want {
+					t.Fatalf("degree %d: Insert(%v) = (%d,%v), want (%d,%v)", degree, iv, old, replaced, want, ok)
+				}
+				model[iv] = i
+			}
+
+			if i%100 == 0 {
+				if err := tree.Verify(); err != nil {
+					t.Fatalf("degree %d: Verify() = %v", degree, err)
+				}
+				a := r.Intn(1100)
+				b := a + r.Intn(30)
+				got := collect(func(fn func(Interval[int], int) bool) { tree.Overlapping(a, b, fn) })
+				if want := bruteOverlapping(model, a, b); !slices.Equal(got, want) {
+					t.Fatalf("degree %d: Overlapping(%d,%d) = %v, want %v", degree, a, b, got, want)
+				}
+				got = collect(func(fn func(Interval[int], int) bool) { tree.Stabbing(a, fn) })
+				if want := bruteOverlapping(model, a, a); !slices.Equal(got, want) {
+					t.Fatalf("degree %d: Stabbing(%d) = %v, want %v", degree, a, got, want)
+				}
+			}
+		}
+		if tree.Len() != len(model) {
+			t.Fatalf("degree %d: Len() = %d, want %d", degree, tree.Len(), len(model))
+		}
+	}
+}
+
+func TestOverlappingClosedBounds(t *testing.T) {
+	tree := New[int, string](2)
+	tree.Insert(1, 3, "a")
+	tree.Insert(3, 3, "b")
+	tree.Insert(5, 8, "c")
+	tree.Insert(1, 10, "d")
+
+	tests := []struct {
+		a, b int
+		want []Interval[int]
+	}{
+		{3, 3, []Interval[int]{{1, 3}, {1, 10}, {3, 3}}},
+		{4, 4, []Interval[int]{{1, 10}}},
+		{8, 20, []Interval[int]{{1, 10}, {5, 8}}},
+		{11, 20, nil},
+		{-5, 0, nil},
+	}
+	for _, tc := range tests {
+		got := collect(func(fn func(Interval[int], string) bool) { tree.Overlapping(tc.a, tc.b, fn) })
+		if !slices.Equal(got, tc.want) {
+			t.Fatalf("Overlapping(%d,%d) = %v, want %v", tc.a, tc.b, got, tc.want)
+		}
+	}
+
+	if v, ok := tree.Get(3, 3); !ok || v != "b" {
+		t.Fatalf("Get(3,3) = (%q,%v), want (\"b\",true)", v, ok)
+	}
+	tree.Delete(1, 10)
+	got := collect(func(fn func(Interval[int], string) bool) { tree.Stabbing(4, fn) })
+	if len(got) != 0 {
+		t.Fatalf("Stabbing(4) after Delete = %v, want none", got)
+	}
+	if err := tree.Verify(); err != nil {
+		t.Fatalf("Verify() = %v", err)
+	}
+}
+
+func TestInsertInvertedPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("Insert with end before start should panic")
+		}
+	}()
+	New[int, int](2).Insert(5, 1, 0)
+}
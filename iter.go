@@ -7,26 +7,169 @@ func (t *BTree[K, V]) Ascend(fn func(k K, v V) bool) {
 	t.ascend(t.root, fn)
 }
 
+// iterFrame 是遍历时显式栈中的一层，只有内部节点入栈，叶子在父节点的一步中直接访问完。
+// 升序时下一步先访问 n.items[i-1]（i > 0），再进入 n.children[i]；
+// 降序时下一步先访问 n.items[i]（i < len(items)），再进入 n.children[i]。
+// 栈深度就是树高，放在调用方的定长数组里，通常不需要分配。
+type iterFrame[K any, V any] struct {
+	n *node[K, V]
+	i int
+}
+
+// iterStackDepth 是遍历栈预留的层数，更深的树由 append 扩容
+const iterStackDepth = 16
+
 func (t *BTree[K, V]) ascend(n *node[K, V], fn func(k K, v V) bool) bool {
-	if n == nil {
-		return true
-	}
 	if n.isLeaf {
-		for _, it := range n.items {
+		return t.ascendLeaf(n, 0, nil, fn)
+	}
+	var buf [iterStackDepth]iterFrame[K, V]
+	return t.ascendStack(append(buf[:0], iterFrame[K, V]{n: n}), nil, fn)
+}
+
+// ascendLeaf 从 n.items[i] 开始升序访问叶子 n，hi 不为 nil 时遇到不小于 *hi 的 key 停止
+func (t *BTree[K, V]) ascendLeaf(n *node[K, V], i int, hi *K, fn func(k K, v V) bool) bool {
+	dead := n.deadFlags()
+	if hi == nil && dead == nil {
+		for _, it := range n.items[i:] {
 			if !fn(it.key, it.value) {
 				return false
 			}
 		}
 		return true
 	}
-	for i, it := range n.items { // 从左开始遍历
-		if !t.ascend(n.children[i], fn) {
+	for ; i < len(n.items); i++ {
+		it := &n.items[i]
+		if hi != nil && !t.lessThan(it.key, *hi) {
+			return false
+		}
+		if (dead == nil || !dead[i]) && !fn(it.key, it.value) {
+			return false
+		}
+	}
+	return true
+}
+
+// ascendStack 从 stack 描述的位置开始升序访问元素，直到遍历结束、遇到不小于 *hi 的 key（hi 不为 nil 时）
+// 或 fn 返回 false
+func (t *BTree[K, V]) ascendStack(stack []iterFrame[K, V], hi *K, fn func(k K, v V) bool) bool {
+	for len(stack) > 0 {
+		f := &stack[len(stack)-1]
+		if f.i > len(f.n.items) {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		if f.i > 0 {
+			it := &f.n.items[f.i-1]
+			if hi != nil && !t.lessThan(it.key, *hi) {
+				return false
+			}
+			if !f.n.isDead(f.i-1) && !fn(it.key, it.value) {
+				return false
+			}
+		}
+		child := f.n.children[f.i]
+		f.i++
+		if !child.isLeaf {
+			stack = append(stack, iterFrame[K, V]{n: child})
+		} else if !t.ascendLeaf(child, 0, hi, fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// AscendRange 按升序遍历 [greaterOrEqual, lessThan) 区间内的元素，fn 返回 false 时停止。
+func (t *BTree[K, V]) AscendRange(greaterOrEqual, lessThan K, fn func(k K, v V) bool) {
+	if t == nil || t.root == nil {
+		return
+	}
+	t.ascendRange(t.root, greaterOrEqual, lessThan, fn)
+}
+
+func (t *BTree[K, V]) ascendRange(n *node[K, V], lo, hi K, fn func(k K, v V) bool) bool {
+	// 先下沉到第一个 >= lo 的位置，左侧的子树不可能落在区间内。
+	// 沿途的内部节点停在 children[i] 之后：下一步访问 items[i]，再进入 children[i+1]
+	var buf [iterStackDepth]iterFrame[K, V]
+	stack := buf[:0]
+	for {
+		i, found := t.findIndex(n, lo)
+		if n.isLeaf {
+			if !t.ascendLeaf(n, i, &hi, fn) {
+				return false
+			}
+			break
+		}
+		stack = append(stack, iterFrame[K, V]{n: n, i: i + 1})
+		if found {
+			break
+		}
+		n = n.children[i]
+	}
+	return t.ascendStack(stack, &hi, fn)
+}
+
+// Descend 按降序遍历所有元素，fn 返回 false 时停止。
+func (t *BTree[K, V]) Descend(fn func(k K, v V) bool) {
+	if t == nil || t.root == nil {
+		return
+	}
+	t.descend(t.root, fn)
+}
+
+func (t *BTree[K, V]) descend(n *node[K, V], fn func(k K, v V) bool) bool {
+	if n.isLeaf {
+		return descendLeaf(n, fn)
+	}
+	var buf [iterStackDepth]iterFrame[K, V]
+	stack := append(buf[:0], iterFrame[K, V]{n: n, i: len(n.items)})
+	for len(stack) > 0 {
+		f := &stack[len(stack)-1]
+		if f.i < 0 {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		if f.i < len(f.n.items) {
+			if it := &f.n.items[f.i]; !f.n.isDead(f.i) && !fn(it.key, it.value) {
+				return false
+			}
+		}
+		child := f.n.children[f.i]
+		f.i--
+		if !child.isLeaf {
+			stack = append(stack, iterFrame[K, V]{n: child, i: len(child.items)})
+		} else if !descendLeaf(child, fn) {
 			return false
 		}
-		if !fn(it.key, it.value) {
+	}
+	return true
+}
+
+// descendLeaf 降序访问叶子 n 中的 items
+func descendLeaf[K any, V any](n *node[K, V], fn func(k K, v V) bool) bool {
+	dead := n.deadFlags()
+	for i := len(n.items) - 1; i >= 0; i-- {
+		if it := &n.items[i]; (dead == nil || !dead[i]) && !fn(it.key, it.value) {
 			return false
 		}
 	}
-	// 处理最右侧的子节点
-	return t.ascend(n.children[len(n.children)-1], fn)
+	return true
+}
+
+// Min 返回最小的 key 及其 value，树为空时 ok 为 false。
+func (t *BTree[K, V]) Min() (key K, value V, ok bool) {
+	t.Ascend(func(k K, v V) bool {
+		key, value, ok = k, v, true
+		return false
+	})
+	return key, value, ok
+}
+
+// Max 返回最大的 key 及其 value，树为空时 ok 为 false。
+func (t *BTree[K, V]) Max() (key K, value V, ok bool) {
+	t.Descend(func(k K, v V) bool {
+		key, value, ok = k, v, true
+		return false
+	})
+	return key, value, ok
 }
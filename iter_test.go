@@ -1,6 +1,9 @@
 package btree
 
-import "testing"
+import (
+	"slices"
+	"testing"
+)
 
 func TestAscend_OrderAndCount(t *testing.T) {
 	tree := NewWithOptions[int, int](DefaultOptions(intLess))
@@ -37,3 +40,43 @@ func TestAscend_OrderAndCount(t *testing.T) {
 		t.Fatalf("Ascend visited %d items, want %d", count, N)
 	}
 }
+
+func TestAscendRange(t *testing.T) {
+	keys := make([]int, 100)
+	for i := range keys {
+		keys[i] = i * 2
+	}
+	tree := buildTree(2, keys...)
+
+	tests := []struct {
+		lo, hi int
+		want   []int
+	}{
+		{10, 20, []int{10, 12, 14, 16, 18}},
+		{9, 15, []int{10, 12, 14}},
+		{-5, 3, []int{0, 2}},
+		{195, 500, []int{196, 198}},
+		{50, 50, nil},
+		{300, 400, nil},
+	}
+
+	for _, tc := range tests {
+		var got []int
+		tree.AscendRange(tc.lo, tc.hi, func(k, v int) bool {
+			got = append(got, k)
+			return true
+		})
+		if !slices.Equal(got, tc.want) {
+			t.Fatalf("AscendRange(%d,%d) = %v, want %v", tc.lo, tc.hi, got, tc.want)
+		}
+	}
+
+	var stopped []int
+	tree.AscendRange(0, 100, func(k, v int) bool {
+		stopped = append(stopped, k)
+		return k < 4
+	})
+	if !slices.Equal(stopped, []int{0, 2, 4}) {
+		t.Fatalf("AscendRange stop early = %v, want [0 2 4]", stopped)
+	}
+}
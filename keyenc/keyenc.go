@@ -0,0 +1,250 @@
+// Package keyenc 把元组编码成保序的 []byte：两个编码结果按 bytes.Compare 比较的顺序
+// 与原元组逐字段比较的顺序一致，因此复合 key 可以直接用 Less 作为 btree 的 LessFunc。
+//
+// 编码规则：
+//   - 整数：8 字节大端，有符号数翻转符号位；
+//   - 浮点数：正数翻转符号位，负数按位取反；-0 编码为 +0，所有 NaN 编码为同一个最小值；
+//   - 字符串与字节串：0x00 转义为 0x00 0xFF，以 0x00 0x01 结尾，保证没有编码是另一个的前缀；
+//   - bool：一个字节 0 或 1；
+//   - time.Time：Unix 秒（同有符号整数）加 4 字节纳秒，时区不保留，解码得到 UTC。
+//
+// 降序字段把该字段编码后的每个字节取反。
+// 比较只在相同模式（字段类型与顺序一致）的 key 之间有意义，编码中不带类型信息。
+package keyenc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"time"
+
+	btree "github.com/ormasia/b-tree"
+)
+
+// Order 是单个字段的排序方向
+type Order bool
+
+const (
+	Asc  Order = false
+	Desc Order = true
+)
+
+var (
+	ErrShortBuffer = errors.New("keyenc: unexpected end of key")
+	ErrInvalid     = errors.New("keyenc: invalid encoding")
+)
+
+const (
+	escape     = 0x00
+	escaped00  = 0xFF
+	terminator = 0x01
+)
+
+// Less 按字节序比较编码后的 key，可直接作为 btree.Options 的 Less
+var Less btree.LessFunc[[]byte] = bytes.Compare
+
+// Encoder 逐字段追加编码。零值可以直接使用。
+type Encoder struct {
+	buf []byte
+}
+
+// NewEncoder 创建一个在 dst 之后追加编码的 Encoder
+func NewEncoder(dst []byte) *Encoder {
+	return &Encoder{buf: dst}
+}
+
+// Key 返回编码结果，之后继续追加会修改同一个底层数组
+func (e *Encoder) Key() []byte {
+	return e.buf
+}
+
+// invert 对从 start 开始的新字节取反
+func (e *Encoder) invert(start int, o Order) *Encoder {
+	if o == Desc {
+		for i := start; i < len(e.buf); i++ {
+			e.buf[i] = ^e.buf[i]
+		}
+	}
+	return e
+}
+
+func (e *Encoder) Uint(v uint64, o Order) *Encoder {
+	start := len(e.buf)
+	e.buf = binary.BigEndian.AppendUint64(e.buf, v)
+	return e.invert(start, o)
+}
+
+func (e *Encoder) Int(v int64, o Order) *Encoder {
+	return e.Uint(uint64(v)^(1<<63), o)
+}
+
+func (e *Encoder) Float(v float64, o Order) *Encoder {
+	return e.Uint(floatBits(v), o)
+}
+
+func (e *Encoder) Bool(v bool, o Order) *Encoder {
+	start := len(e.buf)
+	if v {
+		e.buf = append(e.buf, 1)
+	} else {
+		e.buf = append(e.buf, 0)
+	}
+	return e.invert(start, o)
+}
+
+func (e *Encoder) String(v string, o Order) *Encoder {
+	start := len(e.buf)
+	for i := 0; i < len(v); i++ {
+		if v[i] == escape {
+			e.buf = append(e.buf, escape, escaped00)
+		} else {
+			e.buf = append(e.buf, v[i])
+		}
+	}
+	e.buf = append(e.buf, escape, terminator)
+	return e.invert(start, o)
+}
+
+func (e *Encoder) Bytes(v []byte, o Order) *Encoder {
+	return e.String(string(v), o)
+}
+
+func (e *Encoder) Time(v time.Time, o Order) *Encoder {
+	start := len(e.buf)
+	e.buf = binary.BigEndian.AppendUint64(e.buf, uint64(v.Unix())^(1<<63))
+	e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(v.Nanosecond()))
+	return e.invert(start, o)
+}
+
+// floatBits 把 float64 映射成保序的 uint64
+func floatBits(v float64) uint64 {
+	switch {
+	case math.IsNaN(v):
+		return 0 // 比 -Inf 的编码还小，与 cmp.Compare 把 NaN 排在最前一致
+	case v == 0:
+		v = 0 // -0 与 +0 相等
+	}
+	bits := math.Float64bits(v)
+	if bits&(1<<63) != 0 {
+		return ^bits
+	}
+	return bits | 1<<63
+}
+
+// Decoder 按编码时的字段顺序逐个读出字段，方向必须与编码时一致。
+type Decoder struct {
+	buf []byte
+}
+
+func NewDecoder(key []byte) *Decoder {
+	return &Decoder{buf: key}
+}
+
+// Len 返回还没有读取的字节数
+func (d *Decoder) Len() int {
+	return len(d.buf)
+}
+
+// take 读出 n 个字节，降序字段会先取反，返回的切片是新分配的
+func (d *Decoder) take(n int, o Order) ([]byte, error) {
+	if len(d.buf) < n {
+		return nil, ErrShortBuffer
+	}
+	out := bytes.Clone(d.buf[:n])
+	d.buf = d.buf[n:]
+	if o == Desc {
+		for i := range out {
+			out[i] = ^out[i]
+		}
+	}
+	return out, nil
+}
+
+func (d *Decoder) Uint(o Order) (uint64, error) {
+	b, err := d.take(8, o)
+	if err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(b), nil
+}
+
+func (d *Decoder) Int(o Order) (int64, error) {
+	u, err := d.Uint(o)
+	return int64(u ^ (1 << 63)), err
+}
+
+func (d *Decoder) Float(o Order) (float64, error) {
+	u, err := d.Uint(o)
+	switch {
+	case err != nil:
+		return 0, err
+	case u == 0:
+		return math.NaN(), nil
+	case u&(1<<63) != 0:
+		return math.Float64frombits(u &^ (1 << 63)), nil
+	default:
+		return math.Float64frombits(^u), nil
+	}
+}
+
+func (d *Decoder) Bool(o Order) (bool, error) {
+	b, err := d.take(1, o)
+	if err != nil {
+		return false, err
+	}
+	switch b[0] {
+	case 0:
+		return false, nil
+	case 1:
+		return true, nil
+	}
+	return false, ErrInvalid
+}
+
+func (d *Decoder) String(o Order) (string, error) {
+	b, err := d.Bytes(o)
+	return string(b), err
+}
+
+func (d *Decoder) Bytes(o Order) ([]byte, error) {
+	var flip byte
+	if o == Desc {
+		flip = 0xFF
+	}
+	out := []byte{}
+	for i := 0; i < len(d.buf); i++ {
+		c := d.buf[i] ^ flip
+		if c != escape {
+			out = append(out, c)
+			continue
+		}
+		if i+1 == len(d.buf) {
+			return nil, ErrShortBuffer
+		}
+		switch d.buf[i+1] ^ flip {
+		case escaped00:
+			out = append(out, escape)
+			i++
+		case terminator:
+			d.buf = d.buf[i+2:]
+			return out, nil
+		default:
+			return nil, ErrInvalid
+		}
+	}
+	return nil, ErrShortBuffer
+}
+
+func (d *Decoder) Time(o Order) (time.Time, error) {
+	b, err := d.take(12, o)
+	if err != nil {
+		return time.Time{}, err
+	}
+	sec := int64(binary.BigEndian.Uint64(b) ^ (1 << 63))
+	nsec := binary.BigEndian.Uint32(b[8:])
+	if nsec >= 1e9 {
+		return time.Time{}, ErrInvalid
+	}
+	return time.Unix(sec, int64(nsec)).UTC(), nil
+}
@@ -0,0 +1,207 @@
+package keyenc
+
+import (
+	"bytes"
+	"cmp"
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	btree "github.com/ormasia/b-tree"
+)
+
+// tuple 覆盖所有支持的字段类型
+type tuple struct {
+	i  int64
+	u  uint64
+	f  float64
+	s  string
+	b  []byte
+	ok bool
+	t  time.Time
+}
+
+const fields = 7
+
+// order 取 mask 的第 i 位作为第 i 个字段的方向
+func order(mask uint8, i int) Order {
+	return Order(mask&(1<<i) != 0)
+}
+
+func encode(v tuple, mask uint8) []byte {
+	var e Encoder
+	e.Int(v.i, order(mask, 0)).
+		Uint(v.u, order(mask, 1)).
+		Float(v.f, order(mask, 2)).
+		String(v.s, order(mask, 3)).
+		Bytes(v.b, order(mask, 4)).
+		Bool(v.ok, order(mask, 5)).
+		Time(v.t, order(mask, 6))
+	return e.Key()
+}
+
+// compareTuples 是逐字段比较的参考实现
+func compareTuples(a, b tuple, mask uint8) int {
+	cs := [fields]int{
+		cmp.Compare(a.i, b.i),
+		cmp.Compare(a.u, b.u),
+		cmp.Compare(a.f, b.f),
+		cmp.Compare(a.s, b.s),
+		bytes.Compare(a.b, b.b),
+		cmp.Compare(boolInt(a.ok), boolInt(b.ok)),
+		a.t.Compare(b.t),
+	}
+	for i, c := range cs {
+		if c != 0 {
+			if order(mask, i) == Desc {
+				return -c
+			}
+			return c
+		}
+	}
+	return 0
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func sign(c int) int {
+	return cmp.Compare(c, 0)
+}
+
+func decode(t *testing.T, key []byte, mask uint8) tuple {
+	t.Helper()
+	d := NewDecoder(key)
+	var v tuple
+	var err error
+	check := func(e error) {
+		if e != nil && err == nil {
+			err = e
+		}
+	}
+	v.i, err = d.Int(order(mask, 0))
+	u, e := d.Uint(order(mask, 1))
+	v.u = u
+	check(e)
+	v.f, e = d.Float(order(mask, 2))
+	check(e)
+	v.s, e = d.String(order(mask, 3))
+	check(e)
+	v.b, e = d.Bytes(order(mask, 4))
+	check(e)
+	v.ok, e = d.Bool(order(mask, 5))
+	check(e)
+	v.t, e = d.Time(order(mask, 6))
+	check(e)
+	if err != nil {
+		t.Fatalf("decode(%x) = %v", key, err)
+	}
+	if d.Len() != 0 {
+		t.Fatalf("decode(%x) left %d bytes", key, d.Len())
+	}
+	return v
+}
+
+func fuzzTuple(i int64, u uint64, f float64, s string, b []byte, ok bool, sec int64, nsec uint32) tuple {
+	// 限制在 time.Unix 能精确表示的范围内
+	sec %= 1 << 40
+	return tuple{i, u, f, s, b, ok, time.Unix(sec, int64(nsec%1e9)).UTC()}
+}
+
+func addSeeds(f *testing.F) {
+	f.Add(int64(0), uint64(0), 0.0, "", []byte(nil), false, int64(0), uint32(0),
+		int64(0), uint64(0), math.Copysign(0, -1), "", []byte{}, false, int64(0), uint32(0), uint8(0))
+	f.Add(int64(-1), uint64(1), math.Inf(-1), "a", []byte{0}, true, int64(-100), uint32(5),
+		int64(1), uint64(1), math.NaN(), "a\x00", []byte{0, 0}, true, int64(-100), uint32(4), uint8(0x55))
+	f.Add(int64(math.MinInt64), uint64(math.MaxUint64), -1.5, "ab", []byte{0xFF}, false, int64(1<<33), uint32(999999999),
+		int64(math.MinInt64), uint64(math.MaxUint64), -1.5, "a", []byte{0xFF, 0}, false, int64(1<<33), uint32(999999999), uint8(0xFF))
+}
+
+// FuzzOrderPreserving 验证编码后的字节序与元组的逐字段顺序一致
+func FuzzOrderPreserving(f *testing.F) {
+	addSeeds(f)
+	f.Fuzz(func(t *testing.T,
+		i1 int64, u1 uint64, f1 float64, s1 string, b1 []byte, ok1 bool, sec1 int64, ns1 uint32,
+		i2 int64, u2 uint64, f2 float64, s2 string, b2 []byte, ok2 bool, sec2 int64, ns2 uint32,
+		mask uint8,
+	) {
+		a := fuzzTuple(i1, u1, f1, s1, b1, ok1, sec1, ns1)
+		b := fuzzTuple(i2, u2, f2, s2, b2, ok2, sec2, ns2)
+		want := compareTuples(a, b, mask)
+		if got := sign(Less(encode(a, mask), encode(b, mask))); got != want {
+			t.Fatalf("Less(%+v, %+v, mask %08b) = %d, want %d", a, b, mask, got, want)
+		}
+	})
+}
+
+// FuzzRoundTrip 验证解码得到编码前的值（-0 解码为 +0，NaN 解码为 NaN）
+func FuzzRoundTrip(f *testing.F) {
+	f.Add(int64(-7), uint64(42), math.Copysign(0, -1), "a\x00b", []byte{0, 1, 0xFF}, true, int64(-1), uint32(1), uint8(0x2A))
+	f.Add(int64(0), uint64(0), math.NaN(), "", []byte(nil), false, int64(0), uint32(0), uint8(0))
+	f.Fuzz(func(t *testing.T, i int64, u uint64, fl float64, s string, b []byte, ok bool, sec int64, ns uint32, mask uint8) {
+		in := fuzzTuple(i, u, fl, s, b, ok, sec, ns)
+		out := decode(t, encode(in, mask), mask)
+		if compareTuples(in, out, mask) != 0 || math.IsNaN(in.f) != math.IsNaN(out.f) || (in.f == 0 && math.Signbit(out.f)) {
+			t.Fatalf("round trip %+v -> %+v", in, out)
+		}
+	})
+}
+
+func TestDecodeErrors(t *testing.T) {
+	if _, err := NewDecoder([]byte{1, 2}).Int(Asc); err != ErrShortBuffer {
+		t.Fatalf("Int on short key = %v, want ErrShortBuffer", err)
+	}
+	if _, err := NewDecoder([]byte("abc")).String(Asc); err != ErrShortBuffer {
+		t.Fatalf("String without terminator = %v, want ErrShortBuffer", err)
+	}
+	if _, err := NewDecoder([]byte{'a', 0, 7}).String(Asc); err != ErrInvalid {
+		t.Fatalf("String with bad escape = %v, want ErrInvalid", err)
+	}
+	if _, err := NewDecoder([]byte{2}).Bool(Asc); err != ErrInvalid {
+		t.Fatalf("Bool(2) = %v, want ErrInvalid", err)
+	}
+}
+
+// 复合 key (tenant, timestamp desc, id) 直接作为 btree 的 key
+func TestCompositeKeyInBTree(t *testing.T) {
+	type event struct {
+		tenant string
+		at     time.Time
+		id     uint64
+	}
+	key := func(e event) []byte {
+		return NewEncoder(nil).String(e.tenant, Asc).Time(e.at, Desc).Uint(e.id, Asc).Key()
+	}
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	events := []event{
+		{"acme", base, 2},
+		{"acme", base.Add(time.Hour), 1},
+		{"acme", base, 1},
+		{"ac", base, 9},
+		{"beta", base.Add(-time.Hour), 3},
+		{"beta", base.Add(time.Minute), 3},
+	}
+
+	tree := btree.NewWithOptions[[]byte, event](btree.DefaultOptions(Less))
+	for _, e := range events {
+		tree.Set(key(e), e)
+	}
+	var got []event
+	tree.Ascend(func(k []byte, e event) bool {
+		got = append(got, e)
+		return true
+	})
+
+	want := slices.Clone(events)
+	slices.SortFunc(want, func(a, b event) int {
+		return cmp.Or(cmp.Compare(a.tenant, b.tenant), b.at.Compare(a.at), cmp.Compare(a.id, b.id))
+	})
+	if !slices.Equal(got, want) {
+		t.Fatalf("Ascend = %v, want %v", got, want)
+	}
+}
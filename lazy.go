@@ -0,0 +1,99 @@
+package btree
+
+// markDeleted 是 LazyDelete 模式下的 Delete：只把元素标记为墓碑，不改变树的结构。
+func (t *BTree[K, V]) markDeleted(key K) (old V, deleted bool) {
+	var path []*node[K, V] // 从根到 key 所在节点的路径，用于更新聚合值
+	var target *node[K, V]
+	var idx int
+	t.root = t.mutable(t.root)
+	for n := t.root; n != nil; {
+		if t.monoid != nil {
+			path = append(path, n)
+		}
+		i, found := t.findIndex(n, key)
+		if found {
+			target, idx = n, i
+			break
+		}
+		if n.isLeaf {
+			break
+		}
+		n = t.mutableChild(n, i)
+	}
+	if target == nil || target.isDead(idx) {
+		return old, false
+	}
+
+	old = target.items[idx].value
+	var zero V
+	target.items[idx].value = zero // 墓碑不再持有 value，让 GC 可以回收
+	target.setDead(idx, true)
+	t.size--
+	t.tombstones++
+	for i := len(path) - 1; i >= 0; i-- {
+		t.refresh(path[i])
+	}
+
+	if th := t.options.CompactThreshold; th > 0 && float64(t.tombstones) > th*float64(t.size+t.tombstones) {
+		t.Compact()
+	}
+	return old, true
+}
+
+// Compact 物理删除所有墓碑并重新平衡，返回删除的墓碑个数。
+// 墓碑较少时在原地压缩并修复下溢，墓碑超过一半时直接重建（见 DeleteIf）。
+func (t *BTree[K, V]) Compact() int {
+	if t == nil || t.tombstones == 0 {
+		return 0
+	}
+	n := t.tombstones
+	t.removeIf(func(_ *item[K, V], dead bool) bool {
+		return dead
+	})
+	return n
+}
+
+// Tombstones 返回树中尚未被 Compact 清理的墓碑个数。
+func (t *BTree[K, V]) Tombstones() int {
+	if t == nil {
+		return 0
+	}
+	return t.tombstones
+}
+
+// 墓碑标记保存在 nodeExt.dead 中，与 items 一一对应。节点中从未出现过墓碑时不分配，
+// 因此不使用 LazyDelete 的树不为墓碑付出任何内存。
+
+// deadFlags 返回 n 的墓碑标记，节点中从未出现过墓碑时为 nil
+func (n *node[K, V]) deadFlags() []bool {
+	if n.ext == nil {
+		return nil
+	}
+	return n.ext.dead
+}
+
+// isDead 报告 n.items[i] 是否是墓碑
+func (n *node[K, V]) isDead(i int) bool {
+	dead := n.deadFlags()
+	return dead != nil && dead[i]
+}
+
+// setDead 设置 n.items[i] 的墓碑标记，第一次标记墓碑时才分配标记切片
+func (n *node[K, V]) setDead(i int, dead bool) {
+	if dead || n.deadFlags() != nil {
+		n.syncDead()[i] = dead
+	}
+}
+
+// syncDead 返回与 n.items 等长的墓碑标记：没有时分配，items 变长时在末尾补上 false
+func (n *node[K, V]) syncDead() []bool {
+	d := n.extension().dead
+	if d == nil {
+		d = make([]bool, 0, cap(n.items))
+	}
+	for len(d) < len(n.items) {
+		d = append(d, false)
+	}
+	n.ext.dead = d
+	return d
+}
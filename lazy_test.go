@@ -0,0 +1,253 @@
+package btree
+
+import (
+	"maps"
+	"math/rand"
+	"slices"
+	"testing"
+	"unsafe"
+)
+
+func buildLazyTree(degree int, threshold float64, keys ...int) *BTree[int, int] {
+	opts := OptionsWithDegree(degree, intLess)
+	opts.LazyDelete = true
+	opts.CompactThreshold = threshold
+	tree := NewWithOptions[int, int](opts)
+	for _, k := range keys {
+		tree.Set(k, k)
+	}
+	return tree
+}
+
+func TestLazyDeleteHidesTombstones(t *testing.T) {
+	keys := make([]int, 100)
+	for i := range keys {
+		keys[i] = i
+	}
+	tree := buildLazyTree(3, 0, keys...)
+	before := countKeys(tree.root)
+
+	for i := 0; i < 100; i += 2 {
+		old, deleted := tree.Delete(i)
+		if !deleted || old != i {
+			t.Fatalf("Delete(%d) = (%d,%v), want (%d,true)", i, old, deleted, i)
+		}
+	}
+	if _, deleted := tree.Delete(0); deleted {
+		t.Fatalf("Delete(0) twice reported deleted")
+	}
+
+	// 结构没有变化，墓碑仍然留在节点里
+	if got := countKeys(tree.root); got != before {
+		t.Fatalf("node items = %d after lazy deletes, want %d", got, before)
+	}
+	if tree.Len() != 50 || tree.Tombstones() != 50 {
+		t.Fatalf("Len()=%d Tombstones()=%d, want 50 and 50", tree.Len(), tree.Tombstones())
+	}
+	if _, ok := tree.Get(10); ok {
+		t.Fatalf("Get(10) found a tombstone")
+	}
+
+	var want []int
+	for i := 1; i < 100; i += 2 {
+		want = append(want, i)
+	}
+	assertVerify(t, tree)
+	assertKeys(t, tree, want)
+
+	var ranged []int
+	tree.AscendRange(10, 16, func(k, v int) bool {
+		ranged = append(ranged, k)
+		return true
+	})
+	if !slices.Equal(ranged, []int{11, 13, 15}) {
+		t.Fatalf("AscendRange(10,16) = %v, want [11 13 15]", ranged)
+	}
+}
+
+func TestLazyDeleteSetRevivesTombstone(t *testing.T) {
+	tree := buildLazyTree(2, 0, 1, 2, 3, 4, 5)
+	tree.Delete(3)
+
+	old, replaced := tree.Set(3, 30)
+	if replaced || old != 0 {
+		t.Fatalf("Set on tombstone = (%d,%v), want (0,false)", old, replaced)
+	}
+	if tree.Len() != 5 || tree.Tombstones() != 0 {
+		t.Fatalf("Len()=%d Tombstones()=%d, want 5 and 0", tree.Len(), tree.Tombstones())
+	}
+	if v, ok := tree.Get(3); !ok || v != 30 {
+		t.Fatalf("Get(3) = (%d,%v), want (30,true)", v, ok)
+	}
+	assertVerify(t, tree)
+}
+
+func TestCompact(t *testing.T) {
+	for _, degree := range []int{2, 3, 8} {
+		const N = 500
+		keys := make([]int, N)
+		for i := range keys {
+			keys[i] = i
+		}
+		tree := buildLazyTree(degree, 0, keys...)
+
+		var want []int
+		for i := range keys {
+			if i%3 == 0 {
+				tree.Delete(i)
+			} else {
+				want = append(want, i)
+			}
+		}
+
+		if n := tree.Compact(); n != N-len(want) {
+			t.Fatalf("degree %d: Compact() = %d, want %d", degree, n, N-len(want))
+		}
+		if tree.Tombstones() != 0 || countKeys(tree.root) != len(want) {
+			t.Fatalf("degree %d: %d tombstones and %d items left after Compact, want 0 and %d",
+				degree, tree.Tombstones(), countKeys(tree.root), len(want))
+		}
+		assertVerify(t, tree)
+		assertKeys(t, tree, want)
+
+		if n := tree.Compact(); n != 0 {
+			t.Fatalf("degree %d: second Compact() = %d, want 0", degree, n)
+		}
+	}
+}
+
+func TestCompactThreshold(t *testing.T) {
+	keys := make([]int, 100)
+	for i := range keys {
+		keys[i] = i
+	}
+	tree := buildLazyTree(3, 0.25, keys...)
+
+	// 删除 25 个还没有超过阈值
+	for i := 0; i < 25; i++ {
+		tree.Delete(i)
+	}
+	if tree.Tombstones() != 25 {
+		t.Fatalf("Tombstones() = %d, want 25", tree.Tombstones())
+	}
+
+	// 第 26 个墓碑触发自动压缩
+	tree.Delete(25)
+	if tree.Tombstones() != 0 {
+		t.Fatalf("Tombstones() = %d after crossing threshold, want 0", tree.Tombstones())
+	}
+	if tree.Len() != 74 {
+		t.Fatalf("Len() = %d, want 74", tree.Len())
+	}
+	assertVerify(t, tree)
+	assertKeys(t, tree, keys[26:])
+}
+
+func TestDeleteIfDropsTombstones(t *testing.T) {
+	tree := buildLazyTree(2, 0, 1, 2, 3, 4, 5, 6, 7, 8)
+	tree.Delete(2)
+	tree.Delete(7)
+
+	var seen []int
+	n := tree.DeleteIf(func(k, v int) bool {
+		seen = append(seen, k)
+		return k == 4
+	})
+	if n != 1 {
+		t.Fatalf("DeleteIf = %d, want 1", n)
+	}
+	if !slices.Equal(seen, []int{1, 3, 4, 5, 6, 8}) {
+		t.Fatalf("pred saw %v, want only live keys", seen)
+	}
+	if tree.Tombstones() != 0 {
+		t.Fatalf("Tombstones() = %d after DeleteIf, want 0", tree.Tombstones())
+	}
+	assertVerify(t, tree)
+	assertKeys(t, tree, []int{1, 3, 5, 6, 8})
+}
+
+func TestVerify_DetectTombstoneMismatch(t *testing.T) {
+	tree := buildLazyTree(2, 0, 1, 2, 3, 4, 5)
+	tree.Delete(3)
+
+	tree.tombstones = 0
+	if err := tree.Verify(); err == nil {
+		t.Fatalf("expected Verify() to fail on wrong tombstone count, got nil")
+	}
+
+	tree.tombstones = 1
+	tree.size++
+	if err := tree.Verify(); err == nil {
+		t.Fatalf("expected Verify() to fail on wrong size, got nil")
+	}
+}
+
+// 墓碑标记随元素在分裂、合并、借位和 B* 重新分配中移动，与 map 比较结果
+func TestLazyDeleteAgainstMap(t *testing.T) {
+	for _, s := range []Strategy{Preemptive, BottomUp, BStar} {
+		for _, degree := range []int{2, 3} {
+			opts := OptionsWithDegree(degree, intLess)
+			opts.Strategy = s
+			opts.LazyDelete = true
+			tree := NewWithMonoid[int, int](opts, sumMonoid)
+			model := map[int]int{}
+			rng := rand.New(rand.NewSource(int64(degree)))
+			for i := 0; i < 6000; i++ {
+				k := rng.Intn(400)
+				switch r := rng.Intn(100); {
+				case r < 50:
+					tree.Set(k, i)
+					model[k] = i
+				case r < 95:
+					tree.Delete(k)
+					delete(model, k)
+				case r < 97:
+					// 在副本上整理，原树中的墓碑不受影响
+					clone := tree.Clone()
+					clone.Compact()
+					assertVerify(t, clone)
+				case r < 98:
+					tree.DeleteIf(func(k, v int) bool { return k%7 == 0 })
+					maps.DeleteFunc(model, func(k, v int) bool { return k%7 == 0 })
+				default:
+					tree.Compact()
+				}
+				if i%200 == 0 {
+					assertVerify(t, tree)
+				}
+			}
+			assertVerify(t, tree)
+			assertKeys(t, tree, slices.Sorted(maps.Keys(model)))
+			for k, want := range model {
+				if v, ok := tree.Get(k); !ok || v != want {
+					t.Fatalf("strategy %d, degree %d: Get(%d) = (%d,%v), want %d", s, degree, k, v, ok, want)
+				}
+			}
+		}
+	}
+}
+
+// 墓碑标记不放在 item 中，不使用 LazyDelete 的树不为它付出内存
+func TestTombstonesOutsideItems(t *testing.T) {
+	if size := unsafe.Sizeof(item[int, int]{}); size != 16 {
+		t.Fatalf("item[int, int] is %d bytes, want 16", size)
+	}
+
+	tree := buildTree(2)
+	for i := 0; i < 100; i++ {
+		tree.Set(i, i)
+	}
+	for i := 0; i < 100; i += 2 {
+		tree.Delete(i)
+	}
+	var walk func(n *node[int, int])
+	walk = func(n *node[int, int]) {
+		if n.ext != nil {
+			t.Fatalf("node %v of a tree without LazyDelete has extension state", nodeKeys(n))
+		}
+		for _, child := range n.children {
+			walk(child)
+		}
+	}
+	walk(tree.root)
+}
@@ -2,13 +2,79 @@ package btree
 
 // key 就在当前节点的 items 中，返回索引及 true
 // key 不在当前节点的 items 中，返回应该插入的位置索引及 false
+//
+// 节点较大时用二分查找，每一步只调用一次 Less；小节点线性扫描。
 func (t *BTree[K, V]) findIndex(n *node[K, V], key K) (int, bool) {
-	i := 0
-	for i < len(n.items) && t.lessThan(n.items[i].key, key) {
-		i++
+	if t.search != nil {
+		return t.search(n.items, key, t.options.BinarySearchThreshold)
 	}
-	if i < len(n.items) && t.equal(n.items[i].key, key) {
-		return i, true
+	if threshold := t.options.BinarySearchThreshold; threshold > 0 && len(n.items) >= threshold {
+		lo, hi := 0, len(n.items)
+		for lo < hi {
+			mid := int(uint(lo+hi) >> 1)
+			switch c := t.cmp(n.items[mid].key, key); {
+			case c < 0:
+				lo = mid + 1
+			case c > 0:
+				hi = mid
+			default:
+				return mid, true // key 在树中唯一
+			}
+		}
+		return lo, false
+	}
+
+	for i := range n.items {
+		if c := t.cmp(n.items[i].key, key); c >= 0 {
+			return i, c == 0
+		}
+	}
+	return len(n.items), false
+}
+
+// 下面几个方法增删、搬移节点中的元素，墓碑标记随元素一起移动。
+
+// insertItem 把 it 插入到 n.items[i]，后面的元素依次后移
+func (n *node[K, V]) insertItem(i int, it item[K, V], dead bool) {
+	n.items = insertAt(n.items, i, it)
+	if d := n.deadFlags(); d != nil {
+		n.ext.dead = insertAt(d, i, dead)
+	} else if dead {
+		n.setDead(i, true)
+	}
+}
+
+// removeItem 删除并返回 n.items[i] 以及它是否是墓碑
+func (n *node[K, V]) removeItem(i int) (item[K, V], bool) {
+	it, dead := n.items[i], n.isDead(i)
+	n.items = removeAt(n.items, i)
+	if d := n.deadFlags(); d != nil {
+		n.ext.dead = removeAt(d, i)
+	}
+	return it, dead
+}
+
+// setItem 用 it 替换 n.items[i]
+func (n *node[K, V]) setItem(i int, it item[K, V], dead bool) {
+	n.items[i] = it
+	n.setDead(i, dead)
+}
+
+// appendItems 把 src.items[from:to] 追加到 n 的末尾
+func (n *node[K, V]) appendItems(src *node[K, V], from, to int) {
+	n.items = append(n.items, src.items[from:to]...)
+	if d := src.deadFlags(); d != nil {
+		copy(n.syncDead()[len(n.items)-(to-from):], d[from:to])
+	} else if n.deadFlags() != nil {
+		n.syncDead()
+	}
+}
+
+// truncateItems 只保留 n.items[:k]，清空腾出的位置以便 GC 回收
+func (n *node[K, V]) truncateItems(k int) {
+	clear(n.items[k:])
+	n.items = n.items[:k]
+	if d := n.deadFlags(); d != nil {
+		n.ext.dead = d[:k]
 	}
-	return i, false
 }
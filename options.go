@@ -1,15 +1,123 @@
 package btree
 
+import (
+	"fmt"
+	"slices"
+)
+
 const (
 	minDegree     = 2
 	defaultDegree = 32
+
+	// 节点内 key 数达到该值时 findIndex 改用二分查找
+	defaultBinarySearchThreshold = 8
 )
 
 type LessFunc[K any] func(a, b K) int
 
+// Strategy 决定插入和删除时如何维持节点的 key 数量约束。
+type Strategy int
+
+const (
+	// Preemptive 是默认策略（CLRS）：插入下沉时先分裂路径上的满节点，
+	// 删除下沉时先把路径上的最小节点补齐，一趟下沉即可完成。
+	Preemptive Strategy = iota
+	// BottomUp 是经典的自底向上策略：插入只在节点真正溢出时分裂，并沿路径向上传播；
+	// 删除在回溯时修复下溢的节点。分裂次数更少，节点更满。
+	BottomUp
+	// BStar 在 BottomUp 的基础上实现 B*-tree：节点溢出时先把 key 挪给有空位的兄弟，
+	// 兄弟也满了才把两个节点分成三个，非根节点始终至少 2/3 满；
+	// 删除时对应地把三个节点合并成两个。适合读多写少的大树。
+	BStar
+)
+
 type Options[K any] struct {
 	Degree int
 	Less   LessFunc[K]
+	// Strategy 选择插入/删除的再平衡策略，默认为 Preemptive。
+	Strategy Strategy
+
+	// LazyDelete 为 true 时，Delete 只把元素标记为墓碑，不做任何再平衡；
+	// Get/Ascend/Len 会忽略墓碑，Compact 负责物理删除。
+	LazyDelete bool
+	// CompactThreshold 是墓碑占全部元素的比例上限，超过时 Delete 自动触发 Compact。
+	// 0 表示只在显式调用 Compact 时压缩。仅在 LazyDelete 模式下生效。
+	CompactThreshold float64
+
+	// FastAppend 为 true 时，Set 识别比当前最大 key 还大的追加：沿最右路径直接下沉，不做节点内查找；
+	// 最右路径上的满节点按 90/10 分裂，左边保留大部分 key，顺序导入得到接近满的节点。
+	// 连续追加期间最右路径上的非根节点可以少于 degree-1 个 key（至少 1 个），
+	// 下一次其他修改之前从左兄弟补足，见 settleRightEdge。不能与 BStar 策略同时使用。
+	FastAppend bool
+
+	// BinarySearchThreshold 是节点内改用二分查找的 key 数下限，更小的节点仍然线性扫描：
+	// 几个 key 时线性扫描分支更可预测，通常更快。
+	// 0 表示使用默认值 8，负数表示总是线性扫描。
+	BinarySearchThreshold int
+
+	// NodeBytes 不为 0 时代替 Degree：取满节点（节点头、items 和孩子指针）不超过 NodeBytes 字节的最大 degree，
+	// 至少为 2。估算按 BTree 节点的布局，用于其他树时是近似值。实际使用的 degree 见 Stats。
+	NodeBytes int
+	// KeyBytes 是每个 key 在 item 之外平均占用的字节数，如 string、[]byte 的内容，只用于 NodeBytes 的估算。
+	KeyBytes int
+
+	// BufferSize 是 BufferedBTree 每个内部节点最多缓冲的消息数，0 表示默认值 4*Degree。
+	// 其他树忽略该字段。
+	BufferSize int
+}
+
+// validate 填充默认值并检查 options 是否合法，不合法时 panic
+func (options Options[K]) validate() Options[K] {
+	if options.Degree == 0 {
+		options.Degree = defaultDegree
+	}
+	if options.BinarySearchThreshold == 0 {
+		options.BinarySearchThreshold = defaultBinarySearchThreshold
+	}
+	if options.Less == nil {
+		panic("btree: LessFunc must not be nil")
+	}
+	if options.Degree < minDegree {
+		panic("btree: degree must be >= MinDegree")
+	}
+	if options.FastAppend && options.Strategy == BStar {
+		panic("btree: FastAppend cannot be used with the BStar strategy")
+	}
+	return options
+}
+
+// setFields 返回 options 中设置了非零值的可选字段名，不包括所有树都支持的 Degree、Less、NodeBytes 和 KeyBytes
+func (options Options[K]) setFields() []string {
+	var names []string
+	if options.Strategy != Preemptive {
+		names = append(names, "Strategy")
+	}
+	if options.LazyDelete {
+		names = append(names, "LazyDelete")
+	}
+	if options.CompactThreshold != 0 {
+		names = append(names, "CompactThreshold")
+	}
+	if options.FastAppend {
+		names = append(names, "FastAppend")
+	}
+	if options.BinarySearchThreshold != 0 {
+		names = append(names, "BinarySearchThreshold")
+	}
+	if options.BufferSize != 0 {
+		names = append(names, "BufferSize")
+	}
+	return names
+}
+
+// requireOnly 在 options 设置了 allowed 以外的可选字段时 panic。
+// 只实现了部分选项的树在构造时调用，而不是静默忽略调用方的设置。
+func (options Options[K]) requireOnly(tree string, allowed ...string) {
+	for _, name := range options.setFields() {
+		if !slices.Contains(allowed, name) {
+			panic(fmt.Sprintf("btree: %s does not support Options.%s", tree, name))
+		}
+	}
 }
 
 func DefaultOptions[K any](less LessFunc[K]) Options[K] {
@@ -0,0 +1,50 @@
+package btree
+
+import "cmp"
+
+// OrderedOptions 返回按 cmp.Compare 排序的默认 Options
+func OrderedOptions[K cmp.Ordered]() Options[K] {
+	return DefaultOptions(cmp.Compare[K])
+}
+
+// NewOrdered 创建一棵按 key 自然顺序排序的树，使用默认 Options。
+func NewOrdered[K cmp.Ordered, V any]() *BTree[K, V] {
+	return NewOrderedWithOptions[K, V](OrderedOptions[K]())
+}
+
+// NewOrderedWithOptions 与 NewOrdered 相同，但使用给定的 options；options.Less 总是被替换为 cmp.Compare。
+//
+// 这样创建的树在节点内查找时走专门的代码：直接比较 key，而不是每次比较都通过 LessFunc 间接调用。
+func NewOrderedWithOptions[K cmp.Ordered, V any](options Options[K]) *BTree[K, V] {
+	options.Less = cmp.Compare[K]
+	t := NewWithOptions[K, V](options)
+	t.search = searchOrdered[K, V]
+	return t
+}
+
+// searchOrdered 是 key 为 cmp.Ordered 时的 findIndex。
+// cmp.Compare 会被内联，浮点数的 NaN 也与 cmp.Compare 的顺序一致。
+func searchOrdered[K cmp.Ordered, V any](items []item[K, V], key K, threshold int) (int, bool) {
+	if threshold > 0 && len(items) >= threshold {
+		lo, hi := 0, len(items)
+		for lo < hi {
+			mid := int(uint(lo+hi) >> 1)
+			switch c := cmp.Compare(items[mid].key, key); {
+			case c < 0:
+				lo = mid + 1
+			case c > 0:
+				hi = mid
+			default:
+				return mid, true
+			}
+		}
+		return lo, false
+	}
+
+	for i := range items {
+		if c := cmp.Compare(items[i].key, key); c >= 0 {
+			return i, c == 0
+		}
+	}
+	return len(items), false
+}
@@ -0,0 +1,125 @@
+package btree
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestNewOrdered(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, degree := range []int{2, 5, 32} {
+		opts := OrderedOptions[int]()
+		opts.Degree = degree
+		tree := NewOrderedWithOptions[int, int](opts)
+		model := make(map[int]int)
+		for i := 0; i < 5000; i++ {
+			k := r.Intn(1000)
+			if r.Intn(3) == 0 {
+				tree.Delete(k)
+				delete(model, k)
+			} else {
+				tree.Set(k, i)
+				model[k] = i
+			}
+		}
+		assertVerify(t, tree)
+		assertKeys(t, tree, slices.Sorted(maps.Keys(model)))
+		for k, want := range model {
+			if v, ok := tree.Get(k); !ok || v != want {
+				t.Fatalf("degree %d: Get(%d) = (%d,%v), want (%d,true)", degree, k, v, ok, want)
+			}
+		}
+	}
+}
+
+// 快速路径与 cmp.Compare 对 NaN、±0 和无穷的处理一致
+func TestNewOrderedFloatOrder(t *testing.T) {
+	keys := []float64{3, math.Inf(1), math.NaN(), -1, math.Inf(-1), 0, 2.5}
+	for _, threshold := range []int{-1, 1} {
+		opts := OrderedOptions[float64]()
+		opts.Degree = 2
+		opts.BinarySearchThreshold = threshold
+		tree := NewOrderedWithOptions[float64, int](opts)
+		for i, k := range keys {
+			tree.Set(k, i)
+		}
+		tree.Set(math.Copysign(0, -1), 99) // -0 与 0 相等，覆盖
+
+		var got []float64
+		tree.Ascend(func(k float64, v int) bool {
+			got = append(got, k)
+			return true
+		})
+		want := []float64{math.NaN(), math.Inf(-1), -1, 0, 2.5, 3, math.Inf(1)}
+		if !slices.EqualFunc(got, want, func(a, b float64) bool { return a == b || a != a && b != b }) {
+			t.Fatalf("threshold %d: Ascend = %v, want %v", threshold, got, want)
+		}
+		if v, ok := tree.Get(math.NaN()); !ok || v != 2 {
+			t.Fatalf("threshold %d: Get(NaN) = (%d,%v), want (2,true)", threshold, v, ok)
+		}
+		if v, _ := tree.Get(0); v != 99 {
+			t.Fatalf("threshold %d: Get(0) = %d, want 99", threshold, v)
+		}
+	}
+}
+
+// BenchmarkOrdered 比较 NewOrdered 的快速路径与通过 LessFunc 比较的通用路径
+func BenchmarkOrdered(b *testing.B) {
+	const N = 100000
+	keys := rand.New(rand.NewSource(1)).Perm(N)
+	strs := make([]string, N)
+	for i, k := range keys {
+		strs[i] = fmt.Sprintf("key-%08d", k)
+	}
+
+	for _, degree := range []int{4, 32} {
+		intTrees := map[string]*BTree[int, int]{
+			"generic": NewWithOptions[int, int](OptionsWithDegree(degree, intLess)),
+			"ordered": NewOrderedWithOptions[int, int](OptionsWithDegree[int](degree, nil)),
+		}
+		strTrees := map[string]*BTree[string, int]{
+			"generic": NewWithOptions[string, int](OptionsWithDegree(degree, func(a, b string) int {
+				switch {
+				case a < b:
+					return -1
+				case a > b:
+					return 1
+				}
+				return 0
+			})),
+			"ordered": NewOrderedWithOptions[string, int](OptionsWithDegree[string](degree, nil)),
+		}
+		for _, name := range []string{"generic", "ordered"} {
+			ti, ts := intTrees[name], strTrees[name]
+			for i := range keys {
+				ti.Set(keys[i], i)
+				ts.Set(strs[i], i)
+			}
+			b.Run(fmt.Sprintf("degree=%d/int/%s/Get", degree, name), func(b *testing.B) {
+				i := 0
+				for b.Loop() {
+					ti.Get(keys[i%N])
+					i++
+				}
+			})
+			b.Run(fmt.Sprintf("degree=%d/int/%s/Set", degree, name), func(b *testing.B) {
+				i := 0
+				for b.Loop() {
+					ti.Set(keys[i%N], i)
+					i++
+				}
+			})
+			b.Run(fmt.Sprintf("degree=%d/string/%s/Get", degree, name), func(b *testing.B) {
+				i := 0
+				for b.Loop() {
+					ts.Get(strs[i%N])
+					i++
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,420 @@
+package btree
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// PrefixTree 是专门用于 string / []byte key 的 B-Tree，key 按字节序排序。
+// 每个节点只保存一次节点内所有 key 的公共前缀，元素中只存去掉前缀后的后缀：
+// 路径型的 key（"/tenants/acme/users/1024/..."）往往共享很长的前缀，这样可以显著减少内存。
+// 查找时在每个节点先与前缀比较一次，之后只比较后缀。
+//
+// 节点容量与 BTree 相同：非根节点的 key 数量在 [degree-1, 2*degree-1] 之间。
+// 插入和删除总是自底向上进行；分裂、合并、借位时重新计算相关节点的前缀。
+// 树中保存的是 key 的副本，调用方之后修改传入的 []byte 不会影响树。
+type PrefixTree[K ~string | ~[]byte, V any] struct {
+	root   *prefixNode[V]
+	degree int
+	size   int
+}
+
+// prefixNode 中第 i 个 key 是 prefix + items[i].suffix。
+// prefix 只需是节点内所有 key 的公共前缀，不要求最长：删除元素后不会重新计算。
+type prefixNode[V any] struct {
+	isLeaf   bool
+	prefix   string
+	items    []prefixItem[V]
+	children []*prefixNode[V]
+}
+
+type prefixItem[V any] struct {
+	suffix string
+	value  V
+}
+
+// prefixEntry 是带完整 key 的元素，只在节点之间搬移元素时临时使用
+type prefixEntry[V any] struct {
+	key   string
+	value V
+}
+
+// NewPrefixTree 创建一个 PrefixTree。key 总是按字节序比较，options.Less 必须为 nil；
+// 只支持 Degree 和 NodeBytes/KeyBytes（按 BTree 节点的布局估算），设置了其他选项时 panic。
+func NewPrefixTree[K ~string | ~[]byte, V any](options Options[K]) *PrefixTree[K, V] {
+	if options.Less != nil {
+		panic("btree: PrefixTree orders keys by bytes, Options.Less must be nil")
+	}
+	options.requireOnly("PrefixTree")
+	options = tuneDegree[K, V](options)
+	if options.Degree == 0 {
+		options.Degree = defaultDegree
+	}
+	if options.Degree < minDegree {
+		panic(fmt.Sprintf("btree: Degree must be >= %d", minDegree))
+	}
+	return &PrefixTree[K, V]{degree: options.Degree}
+}
+
+func (t *PrefixTree[K, V]) Len() int {
+	if t == nil {
+		return 0
+	}
+	return t.size
+}
+
+func (t *PrefixTree[K, V]) Clear() {
+	if t == nil {
+		return
+	}
+	t.root = nil
+	t.size = 0
+}
+
+// find 返回 key 在 n 中的位置以及是否找到。
+// 先与前缀比较一次：key 在前缀部分就已经更小或更大时不必再看后缀。
+func (n *prefixNode[V]) find(key string) (int, bool) {
+	p := len(n.prefix)
+	m := min(p, len(key))
+	switch c := strings.Compare(key[:m], n.prefix[:m]); {
+	case c < 0 || (c == 0 && len(key) < p):
+		return 0, false // key 是前缀的真前缀，比节点内所有 key 都小
+	case c > 0:
+		return len(n.items), false
+	}
+	return slices.BinarySearchFunc(n.items, key[p:], func(it prefixItem[V], suffix string) int {
+		return strings.Compare(it.suffix, suffix)
+	})
+}
+
+func (n *prefixNode[V]) key(i int) string {
+	return n.prefix + n.items[i].suffix
+}
+
+func (n *prefixNode[V]) entry(i int) prefixEntry[V] {
+	return prefixEntry[V]{key: n.key(i), value: n.items[i].value}
+}
+
+func (n *prefixNode[V]) entries() []prefixEntry[V] {
+	es := make([]prefixEntry[V], len(n.items))
+	for i := range n.items {
+		es[i] = n.entry(i)
+	}
+	return es
+}
+
+// setEntries 用有序的 es 替换 n 的全部元素，并重新计算前缀。
+// 后缀用 strings.Clone 复制，不会引用 es 中完整 key 的内存。
+func (n *prefixNode[V]) setEntries(es []prefixEntry[V]) {
+	n.prefix = ""
+	if len(es) > 0 {
+		// es 有序，首尾两个 key 的公共前缀就是所有 key 的公共前缀
+		first, last := es[0].key, es[len(es)-1].key
+		p := 0
+		for p < len(first) && p < len(last) && first[p] == last[p] {
+			p++
+		}
+		n.prefix = strings.Clone(first[:p])
+	}
+	items := make([]prefixItem[V], len(es))
+	for i, e := range es {
+		items[i] = prefixItem[V]{suffix: strings.Clone(e.key[len(n.prefix):]), value: e.value}
+	}
+	n.items = items
+}
+
+// insertAt 把 e 插入到第 i 个位置；e.key 不以当前前缀开头时缩短前缀
+func (n *prefixNode[V]) insertAt(i int, e prefixEntry[V]) {
+	if strings.HasPrefix(e.key, n.prefix) {
+		n.items = insertAt(n.items, i, prefixItem[V]{suffix: strings.Clone(e.key[len(n.prefix):]), value: e.value})
+		return
+	}
+	n.setEntries(insertAt(n.entries(), i, e))
+}
+
+// replaceAt 用 e 替换第 i 个元素；e.key 不以当前前缀开头时缩短前缀
+func (n *prefixNode[V]) replaceAt(i int, e prefixEntry[V]) {
+	if strings.HasPrefix(e.key, n.prefix) {
+		n.items[i] = prefixItem[V]{suffix: strings.Clone(e.key[len(n.prefix):]), value: e.value}
+		return
+	}
+	es := n.entries()
+	es[i] = e
+	n.setEntries(es)
+}
+
+// Get
+func (t *PrefixTree[K, V]) Get(key K) (V, bool) {
+	var zero V
+	if t == nil || t.root == nil {
+		return zero, false
+	}
+	k := string(key)
+	n := t.root
+	for {
+		i, found := n.find(k)
+		if found {
+			return n.items[i].value, true
+		}
+		if n.isLeaf {
+			return zero, false
+		}
+		n = n.children[i]
+	}
+}
+
+// Set 插入或更新 key
+func (t *PrefixTree[K, V]) Set(key K, value V) (old V, replaced bool) {
+	if t == nil {
+		return old, false
+	}
+	if t.root == nil {
+		t.root = &prefixNode[V]{isLeaf: true}
+	}
+	old, replaced = t.insert(t.root, prefixEntry[V]{key: string(key), value: value})
+	if !replaced {
+		t.size++
+	}
+	if len(t.root.items) > 2*t.degree-1 {
+		t.root = &prefixNode[V]{children: []*prefixNode[V]{t.root}}
+		t.splitChild(t.root, 0)
+	}
+	return old, replaced
+}
+
+// insert 把 e 插入以 n 为根的子树，孩子溢出时在回溯途中分裂
+func (t *PrefixTree[K, V]) insert(n *prefixNode[V], e prefixEntry[V]) (old V, replaced bool) {
+	i, found := n.find(e.key)
+	if found {
+		old = n.items[i].value
+		n.items[i].value = e.value
+		return old, true
+	}
+	if n.isLeaf {
+		n.insertAt(i, e)
+		return old, false
+	}
+
+	old, replaced = t.insert(n.children[i], e)
+	if len(n.children[i].items) > 2*t.degree-1 {
+		t.splitChild(n, i)
+	}
+	return old, replaced
+}
+
+// splitChild 把溢出的 parent.children[index] 从中间分成两个节点，中间的 key 上浮到 parent。
+// 两半各自的公共前缀通常比原节点更长，分别重新计算。
+func (t *PrefixTree[K, V]) splitChild(parent *prefixNode[V], index int) {
+	child := parent.children[index]
+	es := child.entries()
+	mid := len(es) / 2
+
+	right := &prefixNode[V]{isLeaf: child.isLeaf}
+	right.setEntries(es[mid+1:])
+	child.setEntries(es[:mid])
+	if !child.isLeaf {
+		right.children = slices.Clone(child.children[mid+1:])
+		clear(child.children[mid+1:])
+		child.children = child.children[:mid+1]
+	}
+
+	parent.insertAt(index, es[mid])
+	parent.children = insertAt(parent.children, index+1, right)
+}
+
+// Delete 删除 key
+func (t *PrefixTree[K, V]) Delete(key K) (old V, deleted bool) {
+	if t == nil || t.root == nil {
+		return old, false
+	}
+	old, deleted = t.delete(t.root, string(key))
+	if !deleted {
+		return old, false
+	}
+	t.size--
+	for t.root != nil && len(t.root.items) == 0 {
+		if t.root.isLeaf {
+			t.root = nil
+		} else {
+			t.root = t.root.children[0]
+		}
+	}
+	return old, true
+}
+
+// delete 在以 n 为根的子树中删除 key，孩子下溢时在回溯途中修复。
+// 内部节点中的 key 用左子树的最大元素（前驱）替换。
+func (t *PrefixTree[K, V]) delete(n *prefixNode[V], key string) (old V, deleted bool) {
+	i, found := n.find(key)
+	if n.isLeaf {
+		if !found {
+			return old, false
+		}
+		old = n.items[i].value
+		n.items = removeAt(n.items, i)
+		return old, true
+	}
+
+	if found {
+		old = n.items[i].value
+		n.replaceAt(i, t.popMax(n.children[i]))
+		t.fixChild(n, i)
+		return old, true
+	}
+	old, deleted = t.delete(n.children[i], key)
+	if deleted {
+		t.fixChild(n, i)
+	}
+	return old, deleted
+}
+
+// popMax 删除并返回以 n 为根的子树中最大的元素
+func (t *PrefixTree[K, V]) popMax(n *prefixNode[V]) prefixEntry[V] {
+	if n.isLeaf {
+		e := n.entry(len(n.items) - 1)
+		n.items = removeAt(n.items, len(n.items)-1)
+		return e
+	}
+	last := len(n.children) - 1
+	e := t.popMax(n.children[last])
+	t.fixChild(n, last)
+	return e
+}
+
+// fixChild 修复下溢的 parent.children[idx]：兄弟有富余时两者平分，否则与兄弟合并
+func (t *PrefixTree[K, V]) fixChild(parent *prefixNode[V], idx int) {
+	minItems := t.degree - 1
+	if len(parent.children[idx].items) >= minItems {
+		return
+	}
+	switch {
+	case idx > 0 && len(parent.children[idx-1].items) > minItems:
+		t.rebalance(parent, idx-1)
+	case idx+1 < len(parent.children) && len(parent.children[idx+1].items) > minItems:
+		t.rebalance(parent, idx)
+	case idx+1 < len(parent.children):
+		t.mergeChildren(parent, idx)
+	default:
+		t.mergeChildren(parent, idx-1)
+	}
+}
+
+// rebalance 把 parent.children[idx]、children[idx+1] 与中间的分隔 key 重新平分到两个节点，
+// 相当于一次借多个元素；两个节点和新的分隔 key 的前缀都要重新计算。
+func (t *PrefixTree[K, V]) rebalance(parent *prefixNode[V], idx int) {
+	left, right := parent.children[idx], parent.children[idx+1]
+	es := append(append(left.entries(), parent.entry(idx)), right.entries()...)
+	children := append(slices.Clip(left.children), right.children...)
+
+	mid := len(es) / 2
+	left.setEntries(es[:mid])
+	right.setEntries(es[mid+1:])
+	parent.replaceAt(idx, es[mid])
+	if !left.isLeaf {
+		left.children = slices.Clone(children[:mid+1])
+		right.children = slices.Clone(children[mid+1:])
+	}
+}
+
+// mergeChildren 把 parent.children[idx+1] 与分隔 key 并入 children[idx]。
+// 合并后的公共前缀可能变短，重新计算。
+func (t *PrefixTree[K, V]) mergeChildren(parent *prefixNode[V], idx int) {
+	left, right := parent.children[idx], parent.children[idx+1]
+	left.setEntries(append(append(left.entries(), parent.entry(idx)), right.entries()...))
+	left.children = append(left.children, right.children...)
+
+	parent.items = removeAt(parent.items, idx)
+	parent.children = removeAt(parent.children, idx+1)
+}
+
+// Ascend 按 key 升序遍历，fn 返回 false 时停止
+func (t *PrefixTree[K, V]) Ascend(fn func(k K, v V) bool) {
+	if t == nil || t.root == nil {
+		return
+	}
+	t.ascend(t.root, fn)
+}
+
+func (t *PrefixTree[K, V]) ascend(n *prefixNode[V], fn func(k K, v V) bool) bool {
+	for i := range n.items {
+		if !n.isLeaf && !t.ascend(n.children[i], fn) {
+			return false
+		}
+		if !fn(K(n.key(i)), n.items[i].value) {
+			return false
+		}
+	}
+	if !n.isLeaf {
+		return t.ascend(n.children[len(n.items)], fn)
+	}
+	return true
+}
+
+// Verify 检查 B-Tree 不变式：节点容量、key 严格有序、叶子深度一致以及 size
+func (t *PrefixTree[K, V]) Verify() error {
+	if t == nil || t.root == nil {
+		return nil
+	}
+	leafDepth := -1
+	count := 0
+	if err := t.verifyNode(t.root, true, nil, nil, 0, &leafDepth, &count); err != nil {
+		return err
+	}
+	if count != t.size {
+		return fmt.Errorf("btree: size is %d but nodes hold %d items", t.size, count)
+	}
+	return nil
+}
+
+// verifyNode 递归检查以 n 为根的子树，n 中的 key 必须落在 (minKey, maxKey) 内，nil 表示无界
+func (t *PrefixTree[K, V]) verifyNode(n *prefixNode[V], isRoot bool, minKey, maxKey *string, depth int, leafDepth *int, count *int) error {
+	keyCount := len(n.items)
+	if keyCount > 2*t.degree-1 || (!isRoot && keyCount < t.degree-1) {
+		return fmt.Errorf("btree: node at depth %d has %d keys, expect in [%d,%d]", depth, keyCount, t.degree-1, 2*t.degree-1)
+	}
+	*count += keyCount
+
+	keys := make([]string, keyCount)
+	for i := range n.items {
+		keys[i] = n.key(i)
+		if minKey != nil && keys[i] <= *minKey {
+			return fmt.Errorf("btree: node at depth %d has key %q <= minKey %q", depth, keys[i], *minKey)
+		}
+		if maxKey != nil && keys[i] >= *maxKey {
+			return fmt.Errorf("btree: node at depth %d has key %q >= maxKey %q", depth, keys[i], *maxKey)
+		}
+		if i > 0 && keys[i-1] >= keys[i] {
+			return fmt.Errorf("btree: node at depth %d has unordered keys: %q >= %q", depth, keys[i-1], keys[i])
+		}
+	}
+
+	if n.isLeaf {
+		if len(n.children) != 0 {
+			return fmt.Errorf("btree: leaf node at depth %d has children", depth)
+		}
+		if *leafDepth == -1 {
+			*leafDepth = depth
+		} else if *leafDepth != depth {
+			return fmt.Errorf("btree: leaf nodes have different depths: %d and %d", *leafDepth, depth)
+		}
+		return nil
+	}
+
+	if len(n.children) != keyCount+1 {
+		return fmt.Errorf("btree: internal node at depth %d has %d keys but %d children", depth, keyCount, len(n.children))
+	}
+	for i, child := range n.children {
+		childMin, childMax := minKey, maxKey
+		if i > 0 {
+			childMin = &keys[i-1]
+		}
+		if i < keyCount {
+			childMax = &keys[i]
+		}
+		if err := t.verifyNode(child, false, childMin, childMax, depth+1, leafDepth, count); err != nil {
+			return err
+		}
+	}
+	return nil
+}
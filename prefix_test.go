@@ -0,0 +1,226 @@
+package btree
+
+import (
+	"fmt"
+	"math/rand"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// prefixKeyBytes 返回 PrefixTree 中实际保存的 key 字节数（前缀与后缀之和）
+func prefixKeyBytes[V any](n *prefixNode[V]) int {
+	if n == nil {
+		return 0
+	}
+	total := len(n.prefix)
+	for _, it := range n.items {
+		total += len(it.suffix)
+	}
+	for _, child := range n.children {
+		total += prefixKeyBytes(child)
+	}
+	return total
+}
+
+func pathKey(tenant, user, order int) string {
+	return fmt.Sprintf("/tenants/tenant-%03d/users/%06d/orders/%08d", tenant, user, order)
+}
+
+func TestPrefixTreeSetGetDelete(t *testing.T) {
+	for _, degree := range []int{2, 3, 8} {
+		r := rand.New(rand.NewSource(int64(degree)))
+		tree := NewPrefixTree[string, int](Options[string]{Degree: degree})
+		model := make(map[string]int)
+
+		for i := 0; i < 4000; i++ {
+			// 混合长短不一、互为前缀以及包含 0 字节的 key
+			var k string
+			switch r.Intn(3) {
+			case 0:
+				k = pathKey(r.Intn(3), r.Intn(20), r.Intn(50))
+			case 1:
+				k = pathKey(r.Intn(3), r.Intn(20), 0)[:r.Intn(40)]
+			default:
+				k = string([]byte{byte(r.Intn(3)), byte(r.Intn(3))})
+			}
+
+			if r.Intn(3) == 0 {
+				old, deleted := tree.Delete(k)
+				want, ok := model[k]
+				if deleted != ok || old != want {
+					t.Fatalf("degree %d: Delete(%q) = (%d,%v), want (%d,%v)", degree, k, old, deleted, want, ok)
+				}
+				delete(model, k)
+			} else {
+				old, replaced := tree.Set(k, i)
+				want, ok := model[k]
+				if replaced != ok || old != want {
+					t.Fatalf("degree %d: Set(%q) = (%d,%v), want (%d,%v)", degree, k, old, replaced, want, ok)
+				}
+				model[k] = i
+			}
+			if i%100 == 0 {
+				if err := tree.Verify(); err != nil {
+					t.Fatalf("degree %d: Verify() = %v", degree, err)
+				}
+			}
+		}
+
+		var want []string
+		for k := range model {
+			want = append(want, k)
+		}
+		slices.Sort(want)
+		var got []string
+		tree.Ascend(func(k string, v int) bool {
+			if mv, ok := tree.Get(k); !ok || mv != v || model[k] != v {
+				t.Fatalf("degree %d: Get(%q) = (%d,%v), want (%d,true)", degree, k, mv, ok, model[k])
+			}
+			got = append(got, k)
+			return true
+		})
+		if !slices.Equal(got, want) || tree.Len() != len(want) {
+			t.Fatalf("degree %d: Ascend returned %d keys (Len %d), want %d", degree, len(got), tree.Len(), len(want))
+		}
+
+		for _, k := range want {
+			tree.Delete(k)
+		}
+		if tree.Len() != 0 || tree.root != nil {
+			t.Fatalf("degree %d: tree should be empty, got Len()=%d", degree, tree.Len())
+		}
+	}
+}
+
+func TestPrefixTreeNilAndOptions(t *testing.T) {
+	var nilTree *PrefixTree[string, int]
+	if _, replaced := nilTree.Set("a", 1); replaced || nilTree.Len() != 0 {
+		t.Fatalf("Set on nil tree reported replaced=%v, Len()=%d", replaced, nilTree.Len())
+	}
+
+	if d := NewPrefixTree[string, int](Options[string]{NodeBytes: 512}).degree; d < minDegree {
+		t.Fatalf("degree from NodeBytes = %d", d)
+	}
+	for _, opts := range []Options[string]{
+		{Less: strings.Compare},
+		{Strategy: BottomUp},
+		{Degree: 1},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("NewPrefixTree(%+v) should panic", opts)
+				}
+			}()
+			NewPrefixTree[string, int](opts)
+		}()
+	}
+}
+
+func TestPrefixTreeBytesKeys(t *testing.T) {
+	tree := NewPrefixTree[[]byte, int](Options[[]byte]{Degree: 2})
+	key := []byte("/a/b/c")
+	tree.Set(key, 1)
+	tree.Set([]byte("/a/b/d"), 2)
+	tree.Set([]byte("/a/x"), 3)
+
+	key[1] = 'z' // 树中保存的是副本
+	if v, ok := tree.Get([]byte("/a/b/c")); !ok || v != 1 {
+		t.Fatalf("Get(/a/b/c) = (%d,%v), want (1,true)", v, ok)
+	}
+	var got []string
+	tree.Ascend(func(k []byte, v int) bool {
+		got = append(got, string(k))
+		return true
+	})
+	if want := []string{"/a/b/c", "/a/b/d", "/a/x"}; !slices.Equal(got, want) {
+		t.Fatalf("Ascend = %v, want %v", got, want)
+	}
+}
+
+// 分裂和合并时前缀要随之变长或变短
+func TestPrefixTreePrefixFollowsSplitAndMerge(t *testing.T) {
+	tree := NewPrefixTree[string, int](Options[string]{Degree: 2})
+	for _, k := range []string{"/a/1", "/a/2", "/b/1", "/b/2"} {
+		tree.Set(k, 0)
+	}
+
+	// 根分裂成 [/a/1 /a/2] /b/1 [/b/2]，两半的前缀都变长
+	left, right := tree.root.children[0], tree.root.children[1]
+	if left.prefix != "/a/" || right.prefix != "/b/2" {
+		t.Fatalf("child prefixes after split = %q, %q, want %q, %q", left.prefix, right.prefix, "/a/", "/b/2")
+	}
+
+	tree.Delete("/a/1")
+	tree.Delete("/b/2") // 右孩子下溢并与左孩子合并，前缀变短
+	if !tree.root.isLeaf || tree.root.prefix != "/" {
+		t.Fatalf("root after merge = %+v, want a leaf with prefix %q", tree.root, "/")
+	}
+	if err := tree.Verify(); err != nil {
+		t.Fatalf("Verify() = %v", err)
+	}
+}
+
+func TestPrefixTreeStoresFewerKeyBytes(t *testing.T) {
+	tree := NewPrefixTree[string, int](Options[string]{Degree: 16})
+	full := 0
+	for tenant := 0; tenant < 4; tenant++ {
+		for user := 0; user < 50; user++ {
+			for order := 0; order < 20; order++ {
+				k := pathKey(tenant, user, order)
+				full += len(k)
+				tree.Set(k, order)
+			}
+		}
+	}
+	stored := prefixKeyBytes(tree.root)
+	t.Logf("full key bytes %d, stored %d (%.1f%%)", full, stored, 100*float64(stored)/float64(full))
+	if stored*2 > full {
+		t.Fatalf("stored %d key bytes, want less than half of %d", stored, full)
+	}
+}
+
+// BenchmarkPrefixTreeMemory 比较 PrefixTree 与普通 BTree 存放路径型 key 时的堆内存占用
+func BenchmarkPrefixTreeMemory(b *testing.B) {
+	const N = 100000
+	keys := func(fn func(k string)) {
+		for i := 0; i < N; i++ {
+			fn(pathKey(i%10, i/10%1000, i))
+		}
+	}
+	heapBytes := func(build func() any) float64 {
+		var before, after runtime.MemStats
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+		tree := build()
+		runtime.GC()
+		runtime.ReadMemStats(&after)
+		runtime.KeepAlive(tree)
+		return float64(after.HeapAlloc-before.HeapAlloc) / N
+	}
+
+	b.Run("BTree", func(b *testing.B) {
+		var perItem float64
+		for b.Loop() {
+			perItem = heapBytes(func() any {
+				tree := NewWithOptions[string, int](DefaultOptions(strings.Compare))
+				keys(func(k string) { tree.Set(k, 0) })
+				return tree
+			})
+		}
+		b.ReportMetric(perItem, "bytes/item")
+	})
+	b.Run("PrefixTree", func(b *testing.B) {
+		var perItem float64
+		for b.Loop() {
+			perItem = heapBytes(func() any {
+				tree := NewPrefixTree[string, int](Options[string]{})
+				keys(func(k string) { tree.Set(k, 0) })
+				return tree
+			})
+		}
+		b.ReportMetric(perItem, "bytes/item")
+	})
+}
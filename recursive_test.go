@@ -0,0 +1,335 @@
+package btree
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+// 本文件保留 get、insertNonFull、deleteFromNode 和 ascend 系列改写成循环之前的递归实现，
+// 作为差分测试的参照：对同一串操作，两种实现应当得到结构完全相同的树和相同的返回值。
+// 其中 recDeleteFromInternal 是先找前驱/后继、再按 key 重新下沉删除的两趟做法，也用来对比比较次数。
+
+func (t *BTree[K, V]) recGet(n *node[K, V], key K) (V, bool) {
+	var zero V
+
+	i, found := t.findIndex(n, key)
+
+	if found {
+		if n.isDead(i) {
+			return zero, false
+		}
+		return n.items[i].value, true
+	}
+
+	if n.isLeaf {
+		return zero, false
+	}
+	return t.recGet(n.children[i], key)
+}
+
+// recSet 与 Set 的 Preemptive 分支相同，只是下沉用 recInsertNonFull
+func (t *BTree[K, V]) recSet(key K, value V) (old V, replaced bool) {
+	if t.root == nil {
+		t.root = t.newNode(true)
+	}
+	t.root = t.mutable(t.root)
+	if t.isFull(t.root) {
+		t.grow()
+	}
+	old, replaced = t.recInsertNonFull(t.root, key, value)
+	if !replaced {
+		t.size++
+	}
+	return old, replaced
+}
+
+func (t *BTree[K, V]) recInsertNonFull(n *node[K, V], key K, value V) (old V, replaced bool) {
+	defer t.refresh(n)
+	if n.isLeaf {
+		i, found := t.findIndex(n, key)
+		if found {
+			return t.overwrite(n, i, value)
+		}
+		t.insertItemAt(n, i, item[K, V]{key: key, value: value})
+		return
+	}
+
+	i, found := t.findIndex(n, key)
+	if found {
+		return t.overwrite(n, i, value)
+	}
+
+	child := t.mutableChild(n, i)
+	if t.isFull(child) {
+		t.splitChild(n, i)
+		switch c := t.cmp(key, n.items[i].key); {
+		case c == 0:
+			return t.overwrite(n, i, value)
+		case c > 0:
+			i++
+		}
+	}
+	return t.recInsertNonFull(n.children[i], key, value)
+}
+
+// recDelete 与 Delete 的 Preemptive 分支相同，只是下沉用 recDeleteFromNode
+func (t *BTree[K, V]) recDelete(key K) (old V, deleted bool) {
+	if t.root == nil {
+		return old, false
+	}
+	t.root = t.mutable(t.root)
+	old, deleted = t.recDeleteFromNode(t.root, key)
+	if !deleted {
+		var zero V
+		return zero, false
+	}
+	t.shrinkRoot()
+	t.size--
+	return old, true
+}
+
+func (t *BTree[K, V]) recDeleteFromNode(n *node[K, V], key K) (old V, deleted bool) {
+	defer t.refresh(n)
+	i, found := t.findIndex(n, key)
+
+	if found {
+		if n.isLeaf {
+			return t.deleteFromLeaf(n, i)
+		}
+		return t.recDeleteFromInternal(n, i)
+	}
+	if n.isLeaf {
+		return old, false
+	}
+	return t.recDeleteFromChild(n, i, key)
+}
+
+func (t *BTree[K, V]) recDeleteFromInternal(n *node[K, V], idx int) (old V, deleted bool) {
+	degree := t.options.Degree
+	targetKey := n.items[idx].key
+	old = n.items[idx].value
+
+	if len(n.children[idx].items) >= degree {
+		leftChild := t.mutableChild(n, idx)
+		predNode := leftChild
+		for !predNode.isLeaf {
+			predNode = predNode.children[len(predNode.children)-1]
+		}
+		predItem := predNode.items[len(predNode.items)-1]
+		n.items[idx] = predItem
+		_, deleted = t.recDeleteFromNode(leftChild, predItem.key)
+		return old, deleted
+	}
+
+	if len(n.children[idx+1].items) >= degree {
+		rightChild := t.mutableChild(n, idx+1)
+		succNode := rightChild
+		for !succNode.isLeaf {
+			succNode = succNode.children[0]
+		}
+		succItem := succNode.items[0]
+		n.items[idx] = succItem
+		_, deleted = t.recDeleteFromNode(rightChild, succItem.key)
+		return old, deleted
+	}
+
+	t.mergeChildren(n, idx)
+	return t.recDeleteFromNode(n.children[idx], targetKey)
+}
+
+func (t *BTree[K, V]) recDeleteFromChild(parent *node[K, V], childIndex int, key K) (old V, deleted bool) {
+	degree := t.options.Degree
+	child := t.mutableChild(parent, childIndex)
+
+	if len(child.items) < degree {
+		if childIndex > 0 {
+			if len(parent.children[childIndex-1].items) >= degree {
+				t.borrowFromLeft(parent, childIndex)
+				child = parent.children[childIndex]
+			} else if childIndex+1 < len(parent.children) {
+				if len(parent.children[childIndex+1].items) >= degree {
+					t.borrowFromRight(parent, childIndex)
+					child = parent.children[childIndex]
+				} else {
+					t.mergeChildren(parent, childIndex)
+					child = parent.children[childIndex]
+				}
+			} else {
+				t.mergeChildren(parent, childIndex-1)
+				child = parent.children[childIndex-1]
+			}
+		} else if childIndex+1 < len(parent.children) {
+			if len(parent.children[childIndex+1].items) >= degree {
+				t.borrowFromRight(parent, childIndex)
+				child = parent.children[childIndex]
+			} else {
+				t.mergeChildren(parent, 0)
+				child = parent.children[0]
+			}
+		}
+	}
+	return t.recDeleteFromNode(child, key)
+}
+
+func (t *BTree[K, V]) recAscend(n *node[K, V], fn func(k K, v V) bool) bool {
+	if n.isLeaf {
+		for i, it := range n.items {
+			if !n.isDead(i) && !fn(it.key, it.value) {
+				return false
+			}
+		}
+		return true
+	}
+	for i, it := range n.items {
+		if !t.recAscend(n.children[i], fn) {
+			return false
+		}
+		if !n.isDead(i) && !fn(it.key, it.value) {
+			return false
+		}
+	}
+	return t.recAscend(n.children[len(n.children)-1], fn)
+}
+
+func (t *BTree[K, V]) recAscendRange(n *node[K, V], lo, hi K, fn func(k K, v V) bool) bool {
+	i, found := t.findIndex(n, lo)
+	if !n.isLeaf && !found {
+		if !t.recAscendRange(n.children[i], lo, hi, fn) {
+			return false
+		}
+	}
+	for ; i < len(n.items); i++ {
+		it := n.items[i]
+		if !t.lessThan(it.key, hi) {
+			return false
+		}
+		if !n.isDead(i) && !fn(it.key, it.value) {
+			return false
+		}
+		if !n.isLeaf && !t.recAscendRange(n.children[i+1], lo, hi, fn) {
+			return false
+		}
+	}
+	return true
+}
+
+func (t *BTree[K, V]) recDescend(n *node[K, V], fn func(k K, v V) bool) bool {
+	for i := len(n.items) - 1; i >= 0; i-- {
+		if !n.isLeaf && !t.recDescend(n.children[i+1], fn) {
+			return false
+		}
+		if it := n.items[i]; !n.isDead(i) && !fn(it.key, it.value) {
+			return false
+		}
+	}
+	if !n.isLeaf {
+		return t.recDescend(n.children[0], fn)
+	}
+	return true
+}
+
+// dumpNode 把 n 的结构（key、value、聚合值、孩子）写成字符串，用来比较两棵树的形状
+func dumpNode(n *node[int, int]) string {
+	if n == nil {
+		return "nil"
+	}
+	s := fmt.Sprintf("(%v agg=%d", n.isLeaf, n.agg())
+	for i, it := range n.items {
+		if !n.isLeaf {
+			s += " " + dumpNode(n.children[i])
+		}
+		s += fmt.Sprintf(" %d:%d", it.key, it.value)
+	}
+	if !n.isLeaf {
+		s += " " + dumpNode(n.children[len(n.items)])
+	}
+	return s + ")"
+}
+
+// collect 把遍历函数产生的 key 收集起来，limit 个之后让 fn 返回 false，检查提前停止
+func collect(limit int, walk func(fn func(k, v int) bool)) []int {
+	var keys []int
+	walk(func(k, v int) bool {
+		keys = append(keys, k)
+		return len(keys) < limit
+	})
+	return keys
+}
+
+// checkSameAsRecursive 对同一个 data 描述的操作序列，分别用循环实现和递归实现执行，逐步比较结果
+func checkSameAsRecursive(t *testing.T, data []byte) {
+	if len(data) == 0 {
+		return
+	}
+	degree := 2 + int(data[0]%4)
+	data = data[1:]
+	iter := NewWithMonoid[int, int](OptionsWithDegree(degree, intLess), sumMonoid)
+	rec := NewWithMonoid[int, int](OptionsWithDegree(degree, intLess), sumMonoid)
+
+	for ; len(data) >= 3; data = data[3:] {
+		op, key, arg := data[0]%4, int(data[1]%128), int(data[2])
+		switch op {
+		case 0, 1:
+			o1, r1 := iter.Set(key, arg)
+			o2, r2 := rec.recSet(key, arg)
+			if o1 != o2 || r1 != r2 {
+				t.Fatalf("Set(%d) = (%d,%v), recursive (%d,%v)", key, o1, r1, o2, r2)
+			}
+		case 2:
+			o1, d1 := iter.Delete(key)
+			o2, d2 := rec.recDelete(key)
+			if o1 != o2 || d1 != d2 {
+				t.Fatalf("Delete(%d) = (%d,%v), recursive (%d,%v)", key, o1, d1, o2, d2)
+			}
+		default:
+			if rec.root == nil {
+				continue
+			}
+			v1, ok1 := iter.Get(key)
+			v2, ok2 := rec.recGet(rec.root, key)
+			if v1 != v2 || ok1 != ok2 {
+				t.Fatalf("Get(%d) = (%d,%v), recursive (%d,%v)", key, v1, ok1, v2, ok2)
+			}
+			limit := 1 + arg%32
+			lo, hi := key, key+arg%64
+			pairs := [][2][]int{
+				{collect(limit, iter.Ascend), collect(limit, func(fn func(k, v int) bool) { rec.recAscend(rec.root, fn) })},
+				{collect(limit, iter.Descend), collect(limit, func(fn func(k, v int) bool) { rec.recDescend(rec.root, fn) })},
+				{
+					collect(limit, func(fn func(k, v int) bool) { iter.AscendRange(lo, hi, fn) }),
+					collect(limit, func(fn func(k, v int) bool) { rec.recAscendRange(rec.root, lo, hi, fn) }),
+				},
+			}
+			for _, p := range pairs {
+				if !slices.Equal(p[0], p[1]) {
+					t.Fatalf("traversal with limit %d, range [%d,%d): got %v, recursive %v", limit, lo, hi, p[0], p[1])
+				}
+			}
+		}
+		if a, b := dumpNode(iter.root), dumpNode(rec.root); a != b {
+			t.Fatalf("trees differ:\n  loop:      %s\n  recursive: %s", a, b)
+		}
+	}
+	assertVerify(t, iter)
+	if iter.Len() != rec.Len() {
+		t.Fatalf("Len() = %d, recursive %d", iter.Len(), rec.Len())
+	}
+}
+
+func FuzzIterativeMatchesRecursive(f *testing.F) {
+	f.Add([]byte{0, 0, 1, 1, 0, 2, 2, 0, 3, 3, 2, 2, 0, 3, 1, 9})
+	f.Add([]byte("\x01 the quick brown fox jumps over the lazy dog, then deletes itself"))
+	for seed := range 8 {
+		data := make([]byte, 1+3*200)
+		x := uint32(seed*2654435761 + 1)
+		for i := range data {
+			x ^= x << 13
+			x ^= x >> 17
+			x ^= x << 5
+			data[i] = byte(x)
+		}
+		f.Add(data)
+	}
+	f.Fuzz(checkSameAsRecursive)
+}
@@ -1,16 +1,21 @@
 package btree
 
-func (t *BTree[K, V]) get(n *node[K, V], key K) (V, bool) {
+// get 从 n 开始逐层向下查找 key，hint 可以为 nil（见 GetHint）
+func (t *BTree[K, V]) get(n *node[K, V], key K, hint *PathHint) (V, bool) {
 	var zero V
+	for depth := 0; ; depth++ {
+		i, found := t.findIndexHint(n, key, hint, depth)
 
-	i, found := t.findIndex(n, key)
+		if found {
+			if n.isDead(i) {
+				return zero, false
+			}
+			return n.items[i].value, true
+		}
 
-	if found {
-		return n.items[i].value, true
+		if n.isLeaf {
+			return zero, false
+		}
+		n = n.children[i]
 	}
-
-	if n.isLeaf {
-		return zero, false
-	}
-	return t.get(n.children[i], key)
 }
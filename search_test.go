@@ -1,6 +1,11 @@
 package btree
 
-import "testing"
+import (
+	"fmt"
+	"math/rand"
+	"runtime"
+	"testing"
+)
 
 func intLess(a, b int) int {
 	switch {
@@ -62,9 +67,9 @@ func TestFindIndex(t *testing.T) {
 	n := &node[int, string]{
 		isLeaf: true,
 		items: []item[int, string]{
-			{10, "a"},
-			{20, "b"},
-			{30, "c"},
+			{key: 10, value: "a"},
+			{key: 20, value: "b"},
+			{key: 30, value: "c"},
 		},
 	}
 
@@ -88,6 +93,26 @@ func TestFindIndex(t *testing.T) {
 	}
 }
 
+// 二分查找与线性扫描在各种节点大小下结果一致
+func TestFindIndexBinaryMatchesLinear(t *testing.T) {
+	linear := NewWithOptions[int, int](Options[int]{Less: intLess, BinarySearchThreshold: -1})
+	binary := NewWithOptions[int, int](Options[int]{Less: intLess, BinarySearchThreshold: 1})
+
+	for size := 0; size <= 40; size++ {
+		n := &node[int, int]{isLeaf: true}
+		for i := 0; i < size; i++ {
+			n.items = append(n.items, item[int, int]{key: 2 * i})
+		}
+		for key := -1; key <= 2*size; key++ {
+			li, lok := linear.findIndex(n, key)
+			bi, bok := binary.findIndex(n, key)
+			if li != bi || lok != bok {
+				t.Fatalf("size %d: findIndex(%d) linear=(%d,%v) binary=(%d,%v)", size, key, li, lok, bi, bok)
+			}
+		}
+	}
+}
+
 func TestSetInsertAndUpdate(t *testing.T) {
 	tree := NewWithOptions[int, string](OptionsWithDegree(2, intLess))
 
@@ -239,3 +264,158 @@ func TestSetManyWithSplit(t *testing.T) {
 		t.Fatalf("Len() after overwrite = %d, want %d", tree.Len(), N)
 	}
 }
+
+// TestSetKeyEqualToPromoted 写入的 key 恰好等于 splitChild 上浮的中间 key 时，应当覆盖而不是重复插入
+func TestSetKeyEqualToPromoted(t *testing.T) {
+	root := internalNode([]int{10}, leaf(1, 2, 3), leaf(20))
+	tree := treeFromRoot(root, 2)
+
+	// 左孩子 [1 2 3] 已满，下沉前分裂，2 被提升到根
+	old, replaced := tree.Set(2, 200)
+	if !replaced || old != 2 {
+		t.Fatalf("Set(2) = (%d,%v), want (2,true)", old, replaced)
+	}
+	if tree.Len() != 5 {
+		t.Fatalf("Len() = %d, want 5", tree.Len())
+	}
+	if v, ok := tree.Get(2); !ok || v != 200 {
+		t.Fatalf("Get(2) = (%d,%v), want (200,true)", v, ok)
+	}
+	assertVerify(t, tree)
+	assertKeys(t, tree, []int{1, 2, 3, 10, 20})
+}
+
+// BenchmarkFindIndex 在不同 degree 下比较线性扫描与二分查找，
+// 报告 Get/Set/Insert/Delete 的耗时以及每次操作调用 Less 的次数。
+// 树中是偶数 key：Set 覆盖已有的 key，Insert 插入夹在它们之间的奇数 key。
+func BenchmarkFindIndex(b *testing.B) {
+	const N = 100000
+	keys := rand.New(rand.NewSource(1)).Perm(N)
+
+	for _, degree := range []int{4, 16, 32, 128} {
+		for _, mode := range []struct {
+			name      string
+			threshold int
+		}{
+			{"linear", -1},
+			{"binary", defaultBinarySearchThreshold},
+		} {
+			compares := 0
+			build := func() *BTree[int, int] {
+				tree := NewWithOptions[int, int](Options[int]{
+					Degree: degree,
+					Less: func(a, b int) int {
+						compares++
+						return intLess(a, b)
+					},
+					BinarySearchThreshold: mode.threshold,
+				})
+				for _, k := range keys {
+					tree.Set(2*k, k)
+				}
+				return tree
+			}
+			run := func(op string, fn func(tree *BTree[int, int], i int) (rebuild bool)) {
+				b.Run(fmt.Sprintf("degree=%d/%s/%s", degree, mode.name, op), func(b *testing.B) {
+					tree := build()
+					compares = 0
+					i := 0
+					for b.Loop() {
+						if fn(tree, i%N) {
+							b.StopTimer()
+							saved := compares
+							tree = build()
+							compares = saved
+							b.StartTimer()
+						}
+						i++
+					}
+					b.ReportMetric(float64(compares)/float64(i), "cmps/op")
+				})
+			}
+
+			run("Get", func(tree *BTree[int, int], i int) bool {
+				tree.Get(2 * keys[i])
+				return false
+			})
+			run("Set", func(tree *BTree[int, int], i int) bool {
+				tree.Set(2*keys[i], i)
+				return false
+			})
+			run("Insert", func(tree *BTree[int, int], i int) bool {
+				tree.Set(2*keys[i]+1, i)
+				return i == N-1 // 全部插入后重建，保持树的大小不变
+			})
+			run("Delete", func(tree *BTree[int, int], i int) bool {
+				tree.Delete(2 * keys[i])
+				return i == N-1 // 全部删完后重建
+			})
+		}
+	}
+}
+
+// mallocCounter 累计 b.Loop 中操作本身的内存分配次数，不包括重建树的部分。
+// 分裂和合并只在少数操作中发生，b.ReportAllocs 的整数 allocs/op 看不出差别。
+type mallocCounter struct {
+	total, start uint64
+}
+
+func mallocs() uint64 {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	return ms.Mallocs
+}
+
+func (c *mallocCounter) pause()  { c.total += mallocs() - c.start }
+func (c *mallocCounter) resume() { c.start = mallocs() }
+
+// BenchmarkNodeAllocs 报告随机 Set/Delete 每次操作的平均内存分配次数
+func BenchmarkNodeAllocs(b *testing.B) {
+	const N = 100000
+	keys := rand.New(rand.NewSource(1)).Perm(N)
+
+	for _, degree := range []int{4, 32} {
+		for _, s := range []struct {
+			name     string
+			strategy Strategy
+		}{
+			{"Preemptive", Preemptive},
+			{"BottomUp", BottomUp},
+		} {
+			// op 对第 i 个 key 执行一次操作，每 N 次之前用 reset 重建树
+			run := func(name string, reset func() *BTree[int, int], op func(tree *BTree[int, int], i int)) {
+				b.Run(fmt.Sprintf("degree=%d/%s/%s", degree, s.name, name), func(b *testing.B) {
+					b.ReportAllocs()
+					var tree *BTree[int, int]
+					var c mallocCounter
+					c.resume()
+					i := 0
+					for b.Loop() {
+						if i%N == 0 {
+							b.StopTimer()
+							c.pause()
+							tree = reset()
+							c.resume()
+							b.StartTimer()
+						}
+						op(tree, i%N)
+						i++
+					}
+					c.pause()
+					b.ReportMetric(float64(c.total)/float64(i), "mallocs/op")
+				})
+			}
+
+			run("Set", func() *BTree[int, int] {
+				return buildTreeWithStrategy(degree, s.strategy)
+			}, func(tree *BTree[int, int], i int) {
+				tree.Set(keys[i], i)
+			})
+			run("Delete", func() *BTree[int, int] {
+				return buildTreeWithStrategy(degree, s.strategy, keys...)
+			}, func(tree *BTree[int, int], i int) {
+				tree.Delete(keys[i])
+			})
+		}
+	}
+}
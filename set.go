@@ -0,0 +1,192 @@
+package btree
+
+// Set 是有序集合，直接复用 BTree 的节点结构。value 类型是 struct{}，
+// item 把它放在 key 前面（见 item），每个元素只占一个 K 的大小。
+// 迭代按 key 升序进行；集合运算返回新的 Set，不修改参与运算的集合，
+// 要求两个集合使用相同的 Less，结果沿用 s 的 Options。
+// 集合运算同时按序遍历两个集合，再用 loadSorted 自底向上构造结果，代价与两个集合的大小之和成正比。
+type Set[K any] struct {
+	tree *BTree[K, struct{}]
+}
+
+func NewSet[K any](options Options[K]) *Set[K] {
+	return &Set[K]{tree: NewWithOptions[K, struct{}](options)}
+}
+
+func (s *Set[K]) Len() int {
+	return s.tree.Len()
+}
+
+func (s *Set[K]) Clear() {
+	s.tree.Clear()
+}
+
+// Add 加入 key，返回 key 之前是否不在集合中
+func (s *Set[K]) Add(key K) bool {
+	_, replaced := s.tree.Set(key, struct{}{})
+	return !replaced
+}
+
+// Remove 删除 key，返回 key 之前是否在集合中
+func (s *Set[K]) Remove(key K) bool {
+	_, deleted := s.tree.Delete(key)
+	return deleted
+}
+
+func (s *Set[K]) Has(key K) bool {
+	_, ok := s.tree.Get(key)
+	return ok
+}
+
+// Min 返回最小的 key，集合为空时 ok 为 false
+func (s *Set[K]) Min() (key K, ok bool) {
+	key, _, ok = s.tree.Min()
+	return key, ok
+}
+
+// Max 返回最大的 key，集合为空时 ok 为 false
+func (s *Set[K]) Max() (key K, ok bool) {
+	key, _, ok = s.tree.Max()
+	return key, ok
+}
+
+// Ascend 按升序遍历集合，fn 返回 false 时停止
+func (s *Set[K]) Ascend(fn func(k K) bool) {
+	s.tree.Ascend(func(k K, _ struct{}) bool {
+		return fn(k)
+	})
+}
+
+// AscendRange 按升序遍历 [greaterOrEqual, lessThan) 内的 key，fn 返回 false 时停止
+func (s *Set[K]) AscendRange(greaterOrEqual, lessThan K, fn func(k K) bool) {
+	s.tree.AscendRange(greaterOrEqual, lessThan, func(k K, _ struct{}) bool {
+		return fn(k)
+	})
+}
+
+// Descend 按降序遍历集合，fn 返回 false 时停止
+func (s *Set[K]) Descend(fn func(k K) bool) {
+	s.tree.Descend(func(k K, _ struct{}) bool {
+		return fn(k)
+	})
+}
+
+// Clone 以 O(1) 的代价复制集合，见 BTree.Clone
+func (s *Set[K]) Clone() *Set[K] {
+	return &Set[K]{tree: s.tree.Clone()}
+}
+
+// Union 返回 s 与 other 的并集
+func (s *Set[K]) Union(other *Set[K]) *Set[K] {
+	return s.merge(other, true, true, true)
+}
+
+// Intersect 返回同时在 s 与 other 中的 key
+func (s *Set[K]) Intersect(other *Set[K]) *Set[K] {
+	return s.merge(other, false, true, false)
+}
+
+// Difference 返回在 s 中但不在 other 中的 key
+func (s *Set[K]) Difference(other *Set[K]) *Set[K] {
+	return s.merge(other, true, false, false)
+}
+
+// merge 按升序同时遍历 s 与 other，onlyS、both、onlyOther 分别表示
+// 只在 s 中、两边都有、只在 other 中的 key 是否进入结果
+func (s *Set[K]) merge(other *Set[K], onlyS, both, onlyOther bool) *Set[K] {
+	t := s.tree
+	var theirs []K
+	other.Ascend(func(k K) bool {
+		theirs = append(theirs, k)
+		return true
+	})
+	keys := make([]item[K, struct{}], 0, s.Len()+len(theirs))
+	j := 0
+	s.Ascend(func(k K) bool {
+		for ; j < len(theirs) && t.lessThan(theirs[j], k); j++ {
+			if onlyOther {
+				keys = append(keys, item[K, struct{}]{key: theirs[j]})
+			}
+		}
+		inOther := j < len(theirs) && t.equal(theirs[j], k)
+		if inOther {
+			j++
+		}
+		if (inOther && both) || (!inOther && onlyS) {
+			keys = append(keys, item[K, struct{}]{key: k})
+		}
+		return true
+	})
+	if onlyOther {
+		for _, k := range theirs[j:] {
+			keys = append(keys, item[K, struct{}]{key: k})
+		}
+	}
+
+	result := NewSet[K](t.options)
+	result.tree.loadSorted(keys)
+	return result
+}
+
+// Verify 检查底层 B-Tree 的不变式
+func (s *Set[K]) Verify() error {
+	return s.tree.Verify()
+}
+
+// loadSorted 用严格递增的 items 自底向上构造整棵树，t 必须为空。
+// 每一层取能装下这一层的最少节点数，key 平均分给各节点，节点之间的 key 提升为上一层的分隔 key，
+// 整个过程不做比较。平均分配达不到节点下限时（B* 的下限较高）退回逐个 Set。
+func (t *BTree[K, V]) loadSorted(items []item[K, V]) {
+	maxItems, minItems := t.maxItems(false), t.minItems()
+	// nodesPerLevel 返回这一层有 n 个 key 时的节点数
+	nodesPerLevel := func(n int) int {
+		return (n + maxItems + 1) / (maxItems + 1)
+	}
+	for n := len(items); n > t.maxItems(true); {
+		count := nodesPerLevel(n)
+		if (n-count+1)/count < minItems {
+			for _, it := range items {
+				t.Set(it.key, it.value)
+			}
+			return
+		}
+		n = count - 1
+	}
+
+	t.size = len(items)
+	var children []*node[K, V] // 下一层的节点，构造叶子层时为 nil
+	for len(items) > t.maxItems(true) {
+		count := nodesPerLevel(len(items))
+		perNode := len(items) - (count - 1)
+		nodes := make([]*node[K, V], 0, count)
+		seps := make([]item[K, V], 0, count-1)
+		for j := 0; j < count; j++ {
+			k := perNode / count
+			if j < perNode%count {
+				k++
+			}
+			n := t.newNode(children == nil)
+			n.items = append(n.items, items[:k]...)
+			if children != nil {
+				n.children = append(n.children, children[:k+1]...)
+				children = children[k+1:]
+			}
+			t.refresh(n)
+			nodes = append(nodes, n)
+			items = items[k:]
+			if j+1 < count {
+				seps = append(seps, items[0])
+				items = items[1:]
+			}
+		}
+		items, children = seps, nodes
+	}
+	if len(items) == 0 && children == nil {
+		return
+	}
+	root := t.newNode(children == nil)
+	root.items = append(root.items, items...)
+	root.children = append(root.children, children...)
+	t.refresh(root)
+	t.root = root
+}
@@ -0,0 +1,228 @@
+package btree
+
+import (
+	"maps"
+	"math/rand"
+	"slices"
+	"testing"
+	"unsafe"
+)
+
+func setKeys(s *Set[int]) []int {
+	var keys []int
+	s.Ascend(func(k int) bool {
+		keys = append(keys, k)
+		return true
+	})
+	return keys
+}
+
+func newIntSet(degree int, keys ...int) *Set[int] {
+	s := NewSet[int](OptionsWithDegree(degree, intLess))
+	for _, k := range keys {
+		s.Add(k)
+	}
+	return s
+}
+
+func TestSetAddRemoveHas(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	s := newIntSet(3)
+	model := make(map[int]bool)
+	for i := 0; i < 3000; i++ {
+		k := r.Intn(400)
+		if r.Intn(3) == 0 {
+			if got := s.Remove(k); got != model[k] {
+				t.Fatalf("Remove(%d) = %v, want %v", k, got, model[k])
+			}
+			delete(model, k)
+		} else {
+			if got := s.Add(k); got == model[k] {
+				t.Fatalf("Add(%d) = %v, want %v", k, got, !model[k])
+			}
+			model[k] = true
+		}
+		if s.Has(k) != model[k] {
+			t.Fatalf("Has(%d) = %v, want %v", k, s.Has(k), model[k])
+		}
+	}
+	if err := s.Verify(); err != nil {
+		t.Fatalf("Verify() = %v", err)
+	}
+	want := slices.Sorted(maps.Keys(model))
+	if got := setKeys(s); !slices.Equal(got, want) || s.Len() != len(want) {
+		t.Fatalf("Ascend = %v (Len %d), want %v", got, s.Len(), want)
+	}
+
+	var desc []int
+	s.Descend(func(k int) bool {
+		desc = append(desc, k)
+		return true
+	})
+	slices.Reverse(desc)
+	if !slices.Equal(desc, want) {
+		t.Fatalf("Descend = %v, want reverse of %v", desc, want)
+	}
+	if lo, ok := s.Min(); !ok || lo != want[0] {
+		t.Fatalf("Min() = (%d,%v), want (%d,true)", lo, ok, want[0])
+	}
+	if hi, ok := s.Max(); !ok || hi != want[len(want)-1] {
+		t.Fatalf("Max() = (%d,%v), want (%d,true)", hi, ok, want[len(want)-1])
+	}
+}
+
+func TestSetEmptyMinMax(t *testing.T) {
+	s := newIntSet(2)
+	if _, ok := s.Min(); ok {
+		t.Fatalf("Min() on empty set reported ok")
+	}
+	if _, ok := s.Max(); ok {
+		t.Fatalf("Max() on empty set reported ok")
+	}
+}
+
+// 惰性删除留下的墓碑不能被 Min/Max 返回
+func TestMinMaxSkipTombstones(t *testing.T) {
+	tree := buildLazyTree(2, 0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+	tree.Delete(1)
+	tree.Delete(9)
+	if k, _, ok := tree.Min(); !ok || k != 2 {
+		t.Fatalf("Min() = (%d,%v), want (2,true)", k, ok)
+	}
+	if k, _, ok := tree.Max(); !ok || k != 8 {
+		t.Fatalf("Max() = (%d,%v), want (8,true)", k, ok)
+	}
+}
+
+func TestSetRange(t *testing.T) {
+	s := newIntSet(2, 1, 3, 5, 7, 9, 11)
+	var got []int
+	s.AscendRange(3, 9, func(k int) bool {
+		got = append(got, k)
+		return true
+	})
+	if !slices.Equal(got, []int{3, 5, 7}) {
+		t.Fatalf("AscendRange(3,9) = %v, want [3 5 7]", got)
+	}
+}
+
+func TestSetOperations(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for _, sizes := range [][2]int{{0, 50}, {50, 0}, {200, 30}, {30, 200}, {300, 300}} {
+		a, b := newIntSet(3), newIntSet(4)
+		inA, inB := make(map[int]bool), make(map[int]bool)
+		for i := 0; i < sizes[0]; i++ {
+			k := r.Intn(400)
+			a.Add(k)
+			inA[k] = true
+		}
+		for i := 0; i < sizes[1]; i++ {
+			k := r.Intn(400)
+			b.Add(k)
+			inB[k] = true
+		}
+		wantA, wantB := setKeys(a), setKeys(b)
+
+		var union, inter, diff []int
+		for k := 0; k < 400; k++ {
+			if inA[k] || inB[k] {
+				union = append(union, k)
+			}
+			if inA[k] && inB[k] {
+				inter = append(inter, k)
+			}
+			if inA[k] && !inB[k] {
+				diff = append(diff, k)
+			}
+		}
+		for _, tc := range []struct {
+			name string
+			got  *Set[int]
+			want []int
+		}{
+			{"Union", a.Union(b), union},
+			{"Intersect", a.Intersect(b), inter},
+			{"Difference", a.Difference(b), diff},
+		} {
+			if err := tc.got.Verify(); err != nil {
+				t.Fatalf("sizes %v: %s Verify() = %v", sizes, tc.name, err)
+			}
+			if got := setKeys(tc.got); !slices.Equal(got, tc.want) {
+				t.Fatalf("sizes %v: %s = %v, want %v", sizes, tc.name, got, tc.want)
+			}
+		}
+
+		// 运算不修改参与运算的集合
+		if !slices.Equal(setKeys(a), wantA) || !slices.Equal(setKeys(b), wantB) {
+			t.Fatalf("sizes %v: set operations modified their operands", sizes)
+		}
+	}
+}
+
+func TestSetItemHasNoValueSlot(t *testing.T) {
+	if got, want := unsafe.Sizeof(item[int, struct{}]{}), unsafe.Sizeof(0); got != want {
+		t.Fatalf("item[int, struct{}] is %d bytes, want %d", got, want)
+	}
+}
+
+// loadSorted 构造的树对每种策略、每个大小都满足不变式
+func TestLoadSorted(t *testing.T) {
+	for _, s := range []Strategy{Preemptive, BottomUp, BStar} {
+		for _, degree := range []int{2, 3, 5} {
+			for _, n := range []int{0, 1, 2, 3, 4, 5, 7, 10, 31, 100, 1000, 5000} {
+				items := make([]item[int, int], n)
+				for i := range items {
+					items[i] = item[int, int]{key: 2 * i, value: i}
+				}
+				tree := buildTreeWithStrategy(degree, s)
+				tree.loadSorted(items)
+				assertVerify(t, tree)
+				want := make([]int, n)
+				for i := range want {
+					want[i] = 2 * i
+				}
+				assertKeys(t, tree, want)
+				// 构造出的树可以继续正常修改
+				tree.Set(1, 1)
+				tree.Delete(0)
+				assertVerify(t, tree)
+			}
+		}
+	}
+}
+
+func TestSetOperationsLarge(t *testing.T) {
+	for _, s := range []Strategy{Preemptive, BottomUp, BStar} {
+		opts := OptionsWithDegree(3, intLess)
+		opts.Strategy = s
+		a, b := NewSet[int](opts), NewSet[int](opts)
+		for i := 0; i < 20000; i++ {
+			a.Add(2 * i)
+			b.Add(3 * i)
+		}
+		inA := func(k int) bool { return k%2 == 0 && k < 40000 }
+		inB := func(k int) bool { return k%3 == 0 }
+		for _, tc := range []struct {
+			name string
+			got  *Set[int]
+			in   func(k int) bool
+		}{
+			{"Union", a.Union(b), func(k int) bool { return inA(k) || inB(k) }},
+			{"Intersect", a.Intersect(b), func(k int) bool { return inA(k) && inB(k) }},
+			{"Difference", b.Difference(a), func(k int) bool { return inB(k) && !inA(k) }},
+		} {
+			if err := tc.got.Verify(); err != nil {
+				t.Fatalf("strategy %d: %s Verify() = %v", s, tc.name, err)
+			}
+			var want []int
+			for k := 0; k < 60000; k++ {
+				if tc.in(k) {
+					want = append(want, k)
+				}
+			}
+			if got := setKeys(tc.got); !slices.Equal(got, want) {
+				t.Fatalf("strategy %d: %s has %d keys, want %d", s, tc.name, len(got), len(want))
+			}
+		}
+	}
+}
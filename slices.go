@@ -0,0 +1,18 @@
+package btree
+
+// insertAt 在 s[i] 处插入 v，后面的元素依次后移
+func insertAt[T any](s []T, i int, v T) []T {
+	var zero T
+	s = append(s, zero)
+	copy(s[i+1:], s[i:])
+	s[i] = v
+	return s
+}
+
+// removeAt 删除 s[i]，并清空腾出的末尾位置以便 GC 回收
+func removeAt[T any](s []T, i int) []T {
+	copy(s[i:], s[i+1:])
+	var zero T
+	s[len(s)-1] = zero
+	return s[:len(s)-1]
+}
@@ -0,0 +1,48 @@
+package btree
+
+// Stats 汇总树的形状信息，用于观察节点的填充情况、比较不同的策略。
+type Stats struct {
+	Height     int     // 树高，空树为 0，只有一个叶子根时为 1
+	Nodes      int     // 节点总数
+	Leaves     int     // 叶子节点数
+	Items      int     // 所有节点中的元素个数（包括墓碑）
+	FillFactor float64 // 节点平均装载率：Items / (Nodes * (2*degree-1))
+	Degree     int     // 实际使用的 degree，设置了 Options.NodeBytes 时是推算的结果
+	NodeBytes  int     // 按 Degree 估算的满内部节点字节数，含 Options.KeyBytes
+}
+
+// Stats 遍历整棵树并返回其形状信息。
+func (t *BTree[K, V]) Stats() Stats {
+	var s Stats
+	if t == nil {
+		return s
+	}
+	if t.options.Degree > 0 { // 零值 BTree 没有经过构造函数，没有 degree
+		s.Degree = t.options.Degree
+		s.NodeBytes = fullNodeBytes[K, V](t.options.Degree, t.options.KeyBytes)
+	}
+	if t.root == nil {
+		return s
+	}
+	for n := t.root; ; n = n.children[0] {
+		s.Height++
+		if n.isLeaf {
+			break
+		}
+	}
+	t.collectStats(t.root, &s)
+	s.FillFactor = float64(s.Items) / float64(s.Nodes*(2*t.options.Degree-1))
+	return s
+}
+
+func (t *BTree[K, V]) collectStats(n *node[K, V], s *Stats) {
+	s.Nodes++
+	s.Items += len(n.items)
+	if n.isLeaf {
+		s.Leaves++
+		return
+	}
+	for _, child := range n.children {
+		t.collectStats(child, s)
+	}
+}
@@ -0,0 +1,190 @@
+package btree
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// orderedMap 是 BTree、BPlusTree 与 ArenaBTree 共同的接口，下面的测试对每种实现都运行一遍
+type orderedMap interface {
+	Get(key int) (int, bool)
+	Set(key, value int) (int, bool)
+	Delete(key int) (int, bool)
+	Ascend(fn func(k, v int) bool)
+	AscendRange(greaterOrEqual, lessThan int, fn func(k, v int) bool)
+	Len() int
+	Verify() error
+}
+
+var implementations = []struct {
+	name string
+	new  func(degree int) orderedMap
+}{
+	{"BTree", func(degree int) orderedMap {
+		return NewWithOptions[int, int](OptionsWithDegree(degree, intLess))
+	}},
+	{"BPlusTree", func(degree int) orderedMap {
+		return NewBPlusTree[int, int](OptionsWithDegree(degree, intLess))
+	}},
+	{"ArenaBTree", func(degree int) orderedMap {
+		return NewArenaBTree[int, int](OptionsWithDegree(degree, intLess))
+	}},
+}
+
+func forEachImpl(t *testing.T, fn func(t *testing.T, newMap func(degree int) orderedMap)) {
+	for _, impl := range implementations {
+		t.Run(impl.name, func(t *testing.T) {
+			fn(t, impl.new)
+		})
+	}
+}
+
+func mapKeys(m orderedMap) []int {
+	var keys []int
+	m.Ascend(func(k, v int) bool {
+		keys = append(keys, k)
+		return true
+	})
+	return keys
+}
+
+func TestSuiteSetGetOverwrite(t *testing.T) {
+	forEachImpl(t, func(t *testing.T, newMap func(int) orderedMap) {
+		m := newMap(2)
+		for _, k := range []int{10, 20, 5, 6, 12, 30, 7, 17} {
+			if old, replaced := m.Set(k, k); replaced || old != 0 {
+				t.Fatalf("Set(%d) = (%d,%v), want (0,false)", k, old, replaced)
+			}
+		}
+		if old, replaced := m.Set(12, 120); !replaced || old != 12 {
+			t.Fatalf("Set(12) overwrite = (%d,%v), want (12,true)", old, replaced)
+		}
+		if v, ok := m.Get(12); !ok || v != 120 {
+			t.Fatalf("Get(12) = (%d,%v), want (120,true)", v, ok)
+		}
+		if _, ok := m.Get(11); ok {
+			t.Fatalf("Get(11) found a missing key")
+		}
+		if m.Len() != 8 {
+			t.Fatalf("Len() = %d, want 8", m.Len())
+		}
+		if err := m.Verify(); err != nil {
+			t.Fatalf("Verify() = %v", err)
+		}
+		if got := mapKeys(m); !slices.Equal(got, []int{5, 6, 7, 10, 12, 17, 20, 30}) {
+			t.Fatalf("keys = %v", got)
+		}
+	})
+}
+
+func TestSuiteDeleteToEmpty(t *testing.T) {
+	forEachImpl(t, func(t *testing.T, newMap func(int) orderedMap) {
+		if _, deleted := newMap(2).Delete(1); deleted {
+			t.Fatalf("Delete on empty map reported deleted")
+		}
+
+		const N = 500
+		m := newMap(3)
+		for i := 0; i < N; i++ {
+			m.Set(i, i)
+		}
+		perm := rand.New(rand.NewSource(3)).Perm(N)
+		for n, k := range perm {
+			if old, deleted := m.Delete(k); !deleted || old != k {
+				t.Fatalf("Delete(%d) = (%d,%v), want (%d,true)", k, old, deleted, k)
+			}
+			if _, deleted := m.Delete(k); deleted {
+				t.Fatalf("Delete(%d) twice reported deleted", k)
+			}
+			if m.Len() != N-n-1 {
+				t.Fatalf("Len() = %d, want %d", m.Len(), N-n-1)
+			}
+			if err := m.Verify(); err != nil {
+				t.Fatalf("Verify() after Delete(%d) = %v", k, err)
+			}
+		}
+		if keys := mapKeys(m); len(keys) != 0 {
+			t.Fatalf("keys after deleting everything = %v", keys)
+		}
+	})
+}
+
+func TestSuiteRandomAgainstMap(t *testing.T) {
+	forEachImpl(t, func(t *testing.T, newMap func(int) orderedMap) {
+		for _, degree := range []int{2, 3, 4, 32} {
+			r := rand.New(rand.NewSource(int64(degree)))
+			m := newMap(degree)
+			model := make(map[int]int)
+
+			for i := 0; i < 5000; i++ {
+				k := r.Intn(700)
+				switch r.Intn(3) {
+				case 0:
+					old, deleted := m.Delete(k)
+					want, ok := model[k]
+					if deleted != ok || old != want {
+						t.Fatalf("degree %d: Delete(%d) = (%d,%v), want (%d,%v)", degree, k, old, deleted, want, ok)
+					}
+					delete(model, k)
+				default:
+					old, replaced := m.Set(k, i)
+					want, ok := model[k]
+					if replaced != ok || old != want {
+						t.Fatalf("degree %d: Set(%d) = (%d,%v), want (%d,%v)", degree, k, old, replaced, want, ok)
+					}
+					model[k] = i
+				}
+				if i%250 == 0 {
+					if err := m.Verify(); err != nil {
+						t.Fatalf("degree %d: Verify() = %v", degree, err)
+					}
+				}
+			}
+
+			var want []int
+			for k := range model {
+				want = append(want, k)
+			}
+			slices.Sort(want)
+			if got := mapKeys(m); !slices.Equal(got, want) {
+				t.Fatalf("degree %d: keys mismatch", degree)
+			}
+			for k, v := range model {
+				if got, ok := m.Get(k); !ok || got != v {
+					t.Fatalf("degree %d: Get(%d) = (%d,%v), want (%d,true)", degree, k, got, ok, v)
+				}
+			}
+			if err := m.Verify(); err != nil {
+				t.Fatalf("degree %d: Verify() = %v", degree, err)
+			}
+		}
+	})
+}
+
+func TestSuiteAscendRange(t *testing.T) {
+	forEachImpl(t, func(t *testing.T, newMap func(int) orderedMap) {
+		m := newMap(2)
+		for i := 0; i < 100; i++ {
+			m.Set(i*3, i)
+		}
+
+		var got []int
+		m.AscendRange(10, 25, func(k, v int) bool {
+			got = append(got, k)
+			return true
+		})
+		if !slices.Equal(got, []int{12, 15, 18, 21, 24}) {
+			t.Fatalf("AscendRange(10,25) = %v", got)
+		}
+
+		got = got[:0]
+		m.Ascend(func(k, v int) bool {
+			got = append(got, k)
+			return len(got) < 4
+		})
+		if !slices.Equal(got, []int{0, 3, 6, 9}) {
+			t.Fatalf("Ascend with early stop = %v", got)
+		}
+	})
+}
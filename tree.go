@@ -1,24 +1,29 @@
 package btree
 
 type BTree[K any, V any] struct {
-	root    *node[K, V]
-	options Options[K]
-	size    int
+	root       *node[K, V]
+	options    Options[K]
+	size       int // 存活元素个数，不含墓碑
+	tombstones int // 墓碑个数
+	monoid     *Monoid[V]
+	owner      *owner // 本树可以原地修改的节点的标记，Clone 时更换
+
+	// search 不为 nil 时代替 findIndex 中基于 LessFunc 的查找，见 NewOrdered
+	search func(items []item[K, V], key K, threshold int) (int, bool)
+
+	freelist *FreeList[K, V] // 见 NewWithFreeList，可以为 nil
+
+	spine []*node[K, V] // 插入、删除等记录下沉路径时复用的切片
+
+	// 以下两项只在 FastAppend 时使用，见 append.go
+	rightmost  []*node[K, V] // 上次追加后从根到最右叶子的路径，都属于本树；为空表示没有缓存
+	looseRight bool          // 最右路径上可能有 90/10 分裂留下的、低于下限的节点
 }
 
 func NewWithOptions[K any, V any](options Options[K]) *BTree[K, V] {
-	if options.Degree == 0 {
-		options.Degree = defaultDegree
-	}
-	if options.Less == nil {
-		panic("btree: LessFunc must not be nil")
-	}
-	if options.Degree < minDegree {
-		panic("btree: degree must be >= MinDegree")
-	}
 	return &BTree[K, V]{
 		root:    nil,
-		options: options,
+		options: tuneDegree[K, V](options).validate(),
 		size:    0,
 	}
 }
@@ -34,8 +39,12 @@ func (t *BTree[K, V]) Clear() {
 	if t == nil {
 		return
 	}
-	t.root = nil // allow GC to reclaim nodes 属于go GC特性一旦失去外部联系，自动回收
+	t.freeTree(t.root) // 配置了 FreeList 时尽量回收节点
+	t.root = nil       // allow GC to reclaim nodes 属于go GC特性一旦失去外部联系，自动回收
 	t.size = 0
+	t.tombstones = 0
+	t.forgetRightmost() // 回收的节点可能被重新用作根，缓存的路径必须作废
+	t.looseRight = false
 }
 
 // some helpers for cmparing keys
@@ -60,13 +69,50 @@ func (t *BTree[K, V]) isFull(n *node[K, V]) bool {
 	return len(n.items) >= 2*t.options.Degree-1
 }
 
+// 溢出：非根节点的 items 数量超过 2*degree - 1，只在 BottomUp/BStar 策略中短暂出现
+func (t *BTree[K, V]) overflows(n *node[K, V]) bool {
+	return len(n.items) > 2*t.options.Degree-1
+}
+
+// minItems 返回非根节点至少要有的 key 数：
+// 普通 B-Tree 为 degree-1；B*-tree 为 2/3 满，即 floor(2*(2*degree-1)/3)
+func (t *BTree[K, V]) minItems() int {
+	if t.options.Strategy == BStar {
+		return 2 * (2*t.options.Degree - 1) / 3
+	}
+	return t.options.Degree - 1
+}
+
+// maxItems 返回节点最多能有的 key 数。
+// B*-tree 的根没有兄弟可以分摊，允许装下两个最小节点的量，分裂后两边恰好 2/3 满
+func (t *BTree[K, V]) maxItems(isRoot bool) int {
+	if isRoot && t.options.Strategy == BStar {
+		return 2 * t.minItems()
+	}
+	return 2*t.options.Degree - 1
+}
+
+// nodeCapacity 是非根节点最多可能持有的 key 数：Preemptive 策略为 2*degree-1，
+// 其他策略在分裂（或挪给兄弟）之前会短暂多出一个 key。
+// B* 的根可以更大，超出时由 append 扩容，只影响根这一个节点。
+func (t *BTree[K, V]) nodeCapacity() int {
+	if t.options.Strategy == Preemptive {
+		return t.maxItems(false)
+	}
+	return t.maxItems(false) + 1
+}
+
+func (t *BTree[K, V]) newNode(isLeaf bool) *node[K, V] {
+	return t.allocNode(isLeaf)
+}
+
 // Get
 func (t *BTree[K, V]) Get(key K) (V, bool) {
 	var value V
 	if t == nil || t.root == nil {
 		return value, false
 	}
-	return t.get(t.root, key)
+	return t.get(t.root, key, nil)
 }
 
 // Set
@@ -74,14 +120,35 @@ func (t *BTree[K, V]) Set(key K, value V) (old V, replaced bool) {
 	if t == nil {
 		return old, false
 	}
+	return t.set(key, value, nil)
+}
+
+// set 实现 Set 和 SetHint，hint 可以为 nil
+func (t *BTree[K, V]) set(key K, value V, hint *PathHint) (old V, replaced bool) {
 	if t.root == nil {
-		t.root = newLeafNode[K, V]()
+		t.root = t.newNode(true)
+	}
+	if t.options.FastAppend {
+		if t.appendFast(key, value) {
+			t.size++
+			return old, false
+		}
+		t.endAppends()
 	}
-	// 如果根满了，增长树高
-	if t.isFull(t.root) {
-		t.grow()
+	t.root = t.mutable(t.root)
+	if t.options.Strategy != Preemptive {
+		old, replaced = t.insertBottomUp(t.root, key, value, hint, 0)
+		// 溢出一路传播到根时，分裂根并增长树高
+		if len(t.root.items) > t.maxItems(true) {
+			t.grow()
+		}
+	} else {
+		// 如果根满了，增长树高
+		if t.isFull(t.root) {
+			t.grow()
+		}
+		old, replaced = t.insertNonFull(t.root, key, value, hint)
 	}
-	old, replaced = t.insertNonFull(t.root, key, value)
 	if !replaced {
 		t.size++
 	}
@@ -0,0 +1,32 @@
+package btree
+
+import "unsafe"
+
+// fullNodeBytes 估算一个满的内部节点占用的字节数：节点头、2*degree-1 个 item、2*degree 个孩子指针，
+// 再加上每个 key 在 item 之外占用的 keyBytes（如 string 的内容）
+func fullNodeBytes[K any, V any](degree, keyBytes int) int {
+	var n node[K, V]
+	itemBytes := int(unsafe.Sizeof(item[K, V]{})) + keyBytes
+	return int(unsafe.Sizeof(n)) + (2*degree-1)*itemBytes + 2*degree*int(unsafe.Sizeof(&n))
+}
+
+// degreeForNodeBytes 返回满节点不超过 nodeBytes 的最大 degree，至少为 minDegree
+func degreeForNodeBytes[K any, V any](nodeBytes, keyBytes int) int {
+	var n node[K, V]
+	itemBytes := int(unsafe.Sizeof(item[K, V]{})) + keyBytes
+	ptrBytes := int(unsafe.Sizeof(&n))
+	// header + (2d-1)*item + 2d*ptr <= nodeBytes
+	degree := (nodeBytes - int(unsafe.Sizeof(n)) + itemBytes) / (2 * (itemBytes + ptrBytes))
+	return max(degree, minDegree)
+}
+
+// tuneDegree 在设置了 NodeBytes 时用它推算 Degree，供各个构造函数在 validate 之前调用
+func tuneDegree[K any, V any](options Options[K]) Options[K] {
+	if options.NodeBytes < 0 || options.KeyBytes < 0 {
+		panic("btree: NodeBytes and KeyBytes must be >= 0")
+	}
+	if options.NodeBytes > 0 {
+		options.Degree = degreeForNodeBytes[K, V](options.NodeBytes, options.KeyBytes)
+	}
+	return options
+}
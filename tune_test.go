@@ -0,0 +1,104 @@
+package btree
+
+import (
+	"cmp"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+// checkDegreeFits 检查推算出的 degree 是满节点不超过 nodeBytes 的最大值
+func checkDegreeFits[K any, V any](t *testing.T, name string, nodeBytes, keyBytes int) {
+	t.Helper()
+	d := degreeForNodeBytes[K, V](nodeBytes, keyBytes)
+	if d > minDegree && fullNodeBytes[K, V](d, keyBytes) > nodeBytes {
+		t.Fatalf("%s, %dB: degree %d needs %dB", name, nodeBytes, d, fullNodeBytes[K, V](d, keyBytes))
+	}
+	if fullNodeBytes[K, V](d+1, keyBytes) <= nodeBytes {
+		t.Fatalf("%s, %dB: degree %d is not the largest that fits", name, nodeBytes, d)
+	}
+}
+
+func TestDegreeForNodeBytes(t *testing.T) {
+	for _, nodeBytes := range []int{1, 64, 256, 1000, 4096, 65536} {
+		checkDegreeFits[int, int](t, "int/int", nodeBytes, 0)
+		checkDegreeFits[string, int](t, "string/int", nodeBytes, 0)
+		checkDegreeFits[string, int](t, "string(+24)/int", nodeBytes, 24)
+		checkDegreeFits[int32, [64]byte](t, "int32/[64]byte", nodeBytes, 0)
+	}
+	if d := degreeForNodeBytes[int, int](1, 0); d != minDegree {
+		t.Fatalf("degree for a tiny node = %d, want %d", d, minDegree)
+	}
+	if small, big := degreeForNodeBytes[int, int](4096, 0), degreeForNodeBytes[int, [64]byte](4096, 0); big >= small {
+		t.Fatalf("larger values should give a smaller degree: %d vs %d", big, small)
+	}
+}
+
+func TestNodeBytesOption(t *testing.T) {
+	opts := DefaultOptions(intLess) // Degree 为 32，NodeBytes 优先
+	opts.NodeBytes = 1024
+	tree := NewWithOptions[int, int](opts)
+	want := degreeForNodeBytes[int, int](1024, 0)
+	if s := tree.Stats(); s.Degree != want || s.NodeBytes > 1024 {
+		t.Fatalf("Stats() = %+v, want degree %d within 1024B", s, want)
+	}
+	for i := 0; i < 1000; i++ {
+		tree.Set(i, i)
+	}
+	assertVerify(t, tree)
+
+	opts.KeyBytes = 64
+	if d := NewWithOptions[int, int](opts).Stats().Degree; d >= want {
+		t.Fatalf("KeyBytes should lower the degree: got %d, without KeyBytes %d", d, want)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("negative NodeBytes should panic")
+		}
+	}()
+	opts.NodeBytes = -1
+	NewWithOptions[int, int](opts)
+}
+
+// benchmarkNodeBytesGet 用给定的 NodeBytes 建树，报告推算的 degree 和随机 Get 的耗时
+func benchmarkNodeBytesGet[K any, V any](b *testing.B, less LessFunc[K], keys []K, value V, keyBytes int) {
+	for _, size := range []int{128, 256, 512, 1024, 4096, 16384} {
+		b.Run(fmt.Sprintf("node=%dB", size), func(b *testing.B) {
+			opts := DefaultOptions(less)
+			opts.NodeBytes = size
+			opts.KeyBytes = keyBytes
+			tree := NewWithOptions[K, V](opts)
+			for _, k := range keys {
+				tree.Set(k, value)
+			}
+			i := 0
+			for b.Loop() {
+				tree.Get(keys[i%len(keys)])
+				i++
+			}
+			b.ReportMetric(float64(tree.Stats().Degree), "degree")
+		})
+	}
+}
+
+func BenchmarkNodeBytes(b *testing.B) {
+	const n = 1 << 18
+	perm := rand.New(rand.NewSource(1)).Perm(n)
+	ints := make([]int, n)
+	strs := make([]string, n)
+	for i, k := range perm {
+		ints[i] = k
+		strs[i] = "key-" + strconv.Itoa(k*7919)
+	}
+	b.Run("int/int", func(b *testing.B) {
+		benchmarkNodeBytesGet(b, intLess, ints, 0, 0)
+	})
+	b.Run("int/[64]byte", func(b *testing.B) {
+		benchmarkNodeBytesGet(b, intLess, ints, [64]byte{}, 0)
+	})
+	b.Run("string/int", func(b *testing.B) {
+		benchmarkNodeBytesGet(b, cmp.Compare[string], strs, 0, 12)
+	})
+}
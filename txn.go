@@ -0,0 +1,283 @@
+package btree
+
+import "errors"
+
+var (
+	// ErrTxnClosed 表示事务已经提交或回滚，不能再使用。
+	ErrTxnClosed = errors.New("btree: transaction already committed or rolled back")
+	// ErrInvalidSavepoint 表示保存点不属于当前事务，或者已经被更早的回滚撤销。
+	ErrInvalidSavepoint = errors.New("btree: invalid savepoint")
+)
+
+// Txn 是一棵 BTree（或 ArenaBTree）上的事务。
+//
+// 事务内的写入缓存在私有的写集合中，不会触碰原树：
+// 事务内的读操作能看到自己的写入，Commit 时一次性写回原树，Rollback 直接丢弃写集合，
+// 因此原树的其他读者永远观察不到中间状态。
+// 每次写入都会记录一条 undo 日志，Savepoint/RollbackTo 借此实现嵌套的部分回滚。
+//
+// 事务期间不要绕过事务直接修改原树。与 BTree 一样，Txn 不是并发安全的。
+type Txn[K any, V any] struct {
+	tree txnTree[K, V]
+	// writes 把 key 映射到 entries 中最新一次写入的下标。
+	// 这里不能直接用 BTree[K, txnWrite[V]]：BTree 的 Begin 方法会引用 Txn，
+	// 值类型层层嵌套会形成无限的泛型实例化。
+	writes  *BTree[K, int]
+	entries []txnWrite[V]
+	undo    []txnUndo[K] // 与 entries 一一对应
+	delta   int          // 事务内的写入对 Len 的影响
+	done    bool
+
+	id  *owner // 保存点用它识别所属的事务
+	gen uint64 // RollbackTo 的次数，见 Savepoint
+}
+
+// txnTree 是事务读写原树所用的方法，BTree 和 ArenaBTree 都实现了它。
+type txnTree[K any, V any] interface {
+	Get(key K) (V, bool)
+	Set(key K, value V) (V, bool)
+	Delete(key K) (V, bool)
+	Len() int
+	Ascend(fn func(k K, v V) bool)
+	AscendRange(greaterOrEqual, lessThan K, fn func(k K, v V) bool)
+}
+
+// txnWrite 是写集合中的一条记录，deleted 表示删除。
+type txnWrite[V any] struct {
+	value   V
+	deleted bool
+}
+
+// txnUndo 记录一次写入之前写集合中该 key 的状态。
+type txnUndo[K any] struct {
+	key   K
+	prev  int    // 写入前 key 对应的 entries 下标，-1 表示写集合中原来没有该 key
+	delta int    // 写入前的 delta
+	gen   uint64 // 写入时事务的 gen
+}
+
+// Savepoint 标记事务中的一个位置，用于 RollbackTo。
+//
+// 保存点记录所属的事务、undo 日志的长度 pos 和当时的 gen。回滚到 pos 之前会撤销保存点，
+// 即使 undo 日志之后又长回 pos：此时第 pos 条之前的最后一条记录是回滚之后写入的，gen 比保存点新。
+type Savepoint struct {
+	txn *owner
+	pos int
+	gen uint64
+}
+
+// Begin 开启一个事务。
+func (t *BTree[K, V]) Begin() *Txn[K, V] {
+	return begin[K, V](t, t.options.Less)
+}
+
+func begin[K any, V any](tree txnTree[K, V], less LessFunc[K]) *Txn[K, V] {
+	return &Txn[K, V]{
+		tree:   tree,
+		writes: NewWithOptions[K, int](DefaultOptions(less)),
+		id:     new(owner),
+	}
+}
+
+func (x *Txn[K, V]) checkOpen() {
+	if x.done {
+		panic(ErrTxnClosed)
+	}
+}
+
+// Get 返回 key 在事务视图中的值。
+func (x *Txn[K, V]) Get(key K) (V, bool) {
+	x.checkOpen()
+	if idx, ok := x.writes.Get(key); ok {
+		w := x.entries[idx]
+		if w.deleted {
+			var zero V
+			return zero, false
+		}
+		return w.value, true
+	}
+	return x.tree.Get(key)
+}
+
+// Set 在事务中写入 key，返回值的含义与 BTree.Set 相同。
+func (x *Txn[K, V]) Set(key K, value V) (old V, replaced bool) {
+	old, replaced = x.Get(key)
+	delta := 1
+	if replaced {
+		delta = 0
+	}
+	x.write(key, txnWrite[V]{value: value}, delta)
+	return old, replaced
+}
+
+// Delete 在事务中删除 key，返回值的含义与 BTree.Delete 相同。
+func (x *Txn[K, V]) Delete(key K) (old V, deleted bool) {
+	old, deleted = x.Get(key)
+	if !deleted {
+		return old, false
+	}
+	x.write(key, txnWrite[V]{deleted: true}, -1)
+	return old, true
+}
+
+// write 追加一条写入记录，并记录撤销它所需的 undo 日志。
+func (x *Txn[K, V]) write(key K, w txnWrite[V], delta int) {
+	prev, existed := x.writes.Get(key)
+	if !existed {
+		prev = -1
+	}
+	x.undo = append(x.undo, txnUndo[K]{key: key, prev: prev, delta: x.delta, gen: x.gen})
+	x.entries = append(x.entries, w)
+	x.writes.Set(key, len(x.entries)-1)
+	x.delta += delta
+}
+
+// Len 返回事务视图中的元素个数。
+func (x *Txn[K, V]) Len() int {
+	x.checkOpen()
+	return x.tree.Len() + x.delta
+}
+
+// Ascend 按升序遍历事务视图中的所有元素。
+func (x *Txn[K, V]) Ascend(fn func(k K, v V) bool) {
+	x.checkOpen()
+	var pending []txnEntry[K, V]
+	x.writes.Ascend(func(k K, idx int) bool {
+		pending = append(pending, txnEntry[K, V]{key: k, write: x.entries[idx]})
+		return true
+	})
+	x.merge(pending, x.tree.Ascend, fn)
+}
+
+// AscendRange 按升序遍历事务视图中 [greaterOrEqual, lessThan) 区间内的元素。
+func (x *Txn[K, V]) AscendRange(greaterOrEqual, lessThan K, fn func(k K, v V) bool) {
+	x.checkOpen()
+	var pending []txnEntry[K, V]
+	x.writes.AscendRange(greaterOrEqual, lessThan, func(k K, idx int) bool {
+		pending = append(pending, txnEntry[K, V]{key: k, write: x.entries[idx]})
+		return true
+	})
+	x.merge(pending, func(fn func(k K, v V) bool) {
+		x.tree.AscendRange(greaterOrEqual, lessThan, fn)
+	}, fn)
+}
+
+type txnEntry[K any, V any] struct {
+	key   K
+	write txnWrite[V]
+}
+
+// merge 归并原树的有序遍历与有序的写集合：写集合中的记录覆盖原树中相同的 key。
+func (x *Txn[K, V]) merge(pending []txnEntry[K, V], ascend func(fn func(k K, v V) bool), fn func(k K, v V) bool) {
+	j := 0
+	// emitBefore 输出写集合中所有小于 bound 的记录，bound 为 nil 时输出剩余全部
+	emitBefore := func(bound *K) bool {
+		for ; j < len(pending); j++ {
+			e := pending[j]
+			if bound != nil && !x.writes.lessThan(e.key, *bound) {
+				return true
+			}
+			if !e.write.deleted && !fn(e.key, e.write.value) {
+				j++
+				return false
+			}
+		}
+		return true
+	}
+
+	stopped := false
+	ascend(func(k K, v V) bool {
+		if !emitBefore(&k) {
+			stopped = true
+			return false
+		}
+		if j < len(pending) && x.writes.equal(pending[j].key, k) {
+			e := pending[j]
+			j++
+			if e.write.deleted {
+				return true
+			}
+			v = e.write.value
+		}
+		if !fn(k, v) {
+			stopped = true
+			return false
+		}
+		return true
+	})
+	if !stopped {
+		emitBefore(nil)
+	}
+}
+
+// Savepoint 返回当前位置的保存点。
+func (x *Txn[K, V]) Savepoint() Savepoint {
+	x.checkOpen()
+	return Savepoint{txn: x.id, pos: len(x.undo), gen: x.gen}
+}
+
+// RollbackTo 撤销 sp 之后的所有写入，sp 之前的写入和 sp 本身仍然有效。
+func (x *Txn[K, V]) RollbackTo(sp Savepoint) error {
+	if x.done {
+		return ErrTxnClosed
+	}
+	if !x.valid(sp) {
+		return ErrInvalidSavepoint
+	}
+	for i := len(x.undo) - 1; i >= sp.pos; i-- {
+		u := x.undo[i]
+		if u.prev >= 0 {
+			x.writes.Set(u.key, u.prev)
+		} else {
+			x.writes.Delete(u.key)
+		}
+		x.delta = u.delta
+	}
+	clear(x.undo[sp.pos:])
+	clear(x.entries[sp.pos:])
+	x.undo = x.undo[:sp.pos]
+	x.entries = x.entries[:sp.pos]
+	x.gen++
+	return nil
+}
+
+// valid 报告 sp 是否属于 x，并且没有被更早的回滚撤销
+func (x *Txn[K, V]) valid(sp Savepoint) bool {
+	if sp.txn != x.id || sp.pos > len(x.undo) {
+		return false
+	}
+	return sp.pos == 0 || x.undo[sp.pos-1].gen <= sp.gen
+}
+
+// Commit 把事务中的写入一次性应用到原树，之后事务不能再使用。
+func (x *Txn[K, V]) Commit() error {
+	if x.done {
+		return ErrTxnClosed
+	}
+	x.writes.Ascend(func(k K, idx int) bool {
+		if w := x.entries[idx]; w.deleted {
+			x.tree.Delete(k)
+		} else {
+			x.tree.Set(k, w.value)
+		}
+		return true
+	})
+	x.close()
+	return nil
+}
+
+// Rollback 丢弃事务中的所有写入，之后事务不能再使用。
+func (x *Txn[K, V]) Rollback() error {
+	if x.done {
+		return ErrTxnClosed
+	}
+	x.close()
+	return nil
+}
+
+func (x *Txn[K, V]) close() {
+	x.done = true
+	x.writes = nil
+	x.entries = nil
+	x.undo = nil
+}
@@ -0,0 +1,206 @@
+package btree
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func txnKeys(x *Txn[int, int]) []int {
+	var keys []int
+	x.Ascend(func(k, v int) bool {
+		keys = append(keys, k)
+		return true
+	})
+	return keys
+}
+
+func TestTxnReadsOwnWrites(t *testing.T) {
+	tree := buildTree(2, 1, 2, 3, 4, 5)
+	x := tree.Begin()
+
+	if old, replaced := x.Set(3, 30); !replaced || old != 3 {
+		t.Fatalf("Txn.Set(3) = (%d,%v), want (3,true)", old, replaced)
+	}
+	if old, replaced := x.Set(9, 9); replaced || old != 0 {
+		t.Fatalf("Txn.Set(9) = (%d,%v), want (0,false)", old, replaced)
+	}
+	if old, deleted := x.Delete(1); !deleted || old != 1 {
+		t.Fatalf("Txn.Delete(1) = (%d,%v), want (1,true)", old, deleted)
+	}
+	if _, deleted := x.Delete(42); deleted {
+		t.Fatalf("Txn.Delete(42) reported deleted for a missing key")
+	}
+
+	if v, ok := x.Get(3); !ok || v != 30 {
+		t.Fatalf("Txn.Get(3) = (%d,%v), want (30,true)", v, ok)
+	}
+	if _, ok := x.Get(1); ok {
+		t.Fatalf("Txn.Get(1) found a key deleted in the transaction")
+	}
+	if x.Len() != 5 {
+		t.Fatalf("Txn.Len() = %d, want 5", x.Len())
+	}
+	if got := txnKeys(x); !slices.Equal(got, []int{2, 3, 4, 5, 9}) {
+		t.Fatalf("Txn keys = %v, want [2 3 4 5 9]", got)
+	}
+
+	// 原树在提交前不受影响
+	assertKeys(t, tree, []int{1, 2, 3, 4, 5})
+	if v, _ := tree.Get(3); v != 3 {
+		t.Fatalf("tree.Get(3) = %d before commit, want 3", v)
+	}
+}
+
+func TestTxnCommit(t *testing.T) {
+	tree := buildTree(3, 1, 2, 3, 4, 5)
+	x := tree.Begin()
+	x.Set(3, 30)
+	x.Set(6, 6)
+	x.Delete(1)
+	x.Delete(6) // 事务内新写入的 key 再删除，提交后不应出现
+
+	if err := x.Commit(); err != nil {
+		t.Fatalf("Commit() = %v, want nil", err)
+	}
+	assertVerify(t, tree)
+	assertKeys(t, tree, []int{2, 3, 4, 5})
+	if v, _ := tree.Get(3); v != 30 {
+		t.Fatalf("tree.Get(3) = %d after commit, want 30", v)
+	}
+	if tree.Len() != 4 {
+		t.Fatalf("Len() after commit = %d, want 4", tree.Len())
+	}
+}
+
+func TestTxnRollback(t *testing.T) {
+	tree := buildTree(3, 1, 2, 3)
+	x := tree.Begin()
+	x.Set(4, 4)
+	x.Delete(2)
+
+	if err := x.Rollback(); err != nil {
+		t.Fatalf("Rollback() = %v, want nil", err)
+	}
+	assertKeys(t, tree, []int{1, 2, 3})
+
+	if err := x.Commit(); !errors.Is(err, ErrTxnClosed) {
+		t.Fatalf("Commit() after Rollback = %v, want ErrTxnClosed", err)
+	}
+	if err := x.Rollback(); !errors.Is(err, ErrTxnClosed) {
+		t.Fatalf("Rollback() twice = %v, want ErrTxnClosed", err)
+	}
+}
+
+func TestTxnSavepoints(t *testing.T) {
+	tree := buildTree(2, 1, 2, 3)
+	x := tree.Begin()
+
+	x.Set(4, 4)
+	sp1 := x.Savepoint()
+	x.Set(1, 10)
+	x.Delete(2)
+	sp2 := x.Savepoint()
+	x.Set(5, 5)
+	x.Delete(1)
+
+	if err := x.RollbackTo(sp2); err != nil {
+		t.Fatalf("RollbackTo(sp2) = %v, want nil", err)
+	}
+	if got := txnKeys(x); !slices.Equal(got, []int{1, 3, 4}) {
+		t.Fatalf("keys after RollbackTo(sp2) = %v, want [1 3 4]", got)
+	}
+	if v, _ := x.Get(1); v != 10 {
+		t.Fatalf("Get(1) after RollbackTo(sp2) = %d, want 10", v)
+	}
+	if x.Len() != 3 {
+		t.Fatalf("Len() after RollbackTo(sp2) = %d, want 3", x.Len())
+	}
+
+	if err := x.RollbackTo(sp1); err != nil {
+		t.Fatalf("RollbackTo(sp1) = %v, want nil", err)
+	}
+	if got := txnKeys(x); !slices.Equal(got, []int{1, 2, 3, 4}) {
+		t.Fatalf("keys after RollbackTo(sp1) = %v, want [1 2 3 4]", got)
+	}
+	if v, _ := x.Get(1); v != 1 {
+		t.Fatalf("Get(1) after RollbackTo(sp1) = %d, want 1", v)
+	}
+
+	// sp2 已经被更早的回滚撤销，undo 日志长回原来的长度后仍然无效
+	if err := x.RollbackTo(sp2); !errors.Is(err, ErrInvalidSavepoint) {
+		t.Fatalf("RollbackTo(stale savepoint) = %v, want ErrInvalidSavepoint", err)
+	}
+	x.Set(6, 6)
+	x.Set(7, 7)
+	if err := x.RollbackTo(sp2); !errors.Is(err, ErrInvalidSavepoint) {
+		t.Fatalf("RollbackTo(stale savepoint) after new writes = %v, want ErrInvalidSavepoint", err)
+	}
+	// 回滚到 sp1 本身不会撤销 sp1
+	if err := x.RollbackTo(sp1); err != nil {
+		t.Fatalf("RollbackTo(sp1) again = %v, want nil", err)
+	}
+
+	other := tree.Begin()
+	if err := other.RollbackTo(sp1); !errors.Is(err, ErrInvalidSavepoint) {
+		t.Fatalf("RollbackTo(savepoint of another txn) = %v, want ErrInvalidSavepoint", err)
+	}
+	if err := other.RollbackTo(Savepoint{}); !errors.Is(err, ErrInvalidSavepoint) {
+		t.Fatalf("RollbackTo(zero Savepoint) = %v, want ErrInvalidSavepoint", err)
+	}
+	other.Rollback()
+
+	if err := x.Commit(); err != nil {
+		t.Fatalf("Commit() = %v, want nil", err)
+	}
+	assertVerify(t, tree)
+	assertKeys(t, tree, []int{1, 2, 3, 4})
+}
+
+func TestTxnAscendRangeAndStop(t *testing.T) {
+	keys := make([]int, 50)
+	for i := range keys {
+		keys[i] = i * 2
+	}
+	tree := buildTree(2, keys...)
+	x := tree.Begin()
+	x.Set(11, 11)
+	x.Delete(12)
+	x.Set(14, 140)
+	x.Set(99, 99)
+
+	type kv struct{ k, v int }
+	var got []kv
+	x.AscendRange(10, 20, func(k, v int) bool {
+		got = append(got, kv{k, v})
+		return true
+	})
+	want := []kv{{10, 10}, {11, 11}, {14, 140}, {16, 16}, {18, 18}}
+	if !slices.Equal(got, want) {
+		t.Fatalf("AscendRange(10,20) = %v, want %v", got, want)
+	}
+
+	var first []int
+	x.Ascend(func(k, v int) bool {
+		first = append(first, k)
+		return len(first) < 3
+	})
+	if !slices.Equal(first, []int{0, 2, 4}) {
+		t.Fatalf("Ascend stopped early = %v, want [0 2 4]", first)
+	}
+}
+
+func TestTxnClosedPanics(t *testing.T) {
+	tree := buildTree(2, 1)
+	x := tree.Begin()
+	if err := x.Commit(); err != nil {
+		t.Fatalf("Commit() = %v, want nil", err)
+	}
+
+	defer func() {
+		if r := recover(); r != ErrTxnClosed {
+			t.Fatalf("Get on closed txn panicked with %v, want ErrTxnClosed", r)
+		}
+	}()
+	x.Get(1)
+}
@@ -1,28 +1,63 @@
 package btree
 
+// item 把 value 放在 key 前面：零大小的字段放在结构体末尾时编译器会为它补齐，
+// 放在前面则不占空间，V 为 struct{} 的树（如 Set）每个元素只占一个 K 的大小。
 type item[K any, V any] struct {
-	key   K
 	value V
+	key   K
 }
 
 type node[K any, V any] struct {
 	isLeaf   bool
 	items    []item[K, V]
 	children []*node[K, V]
+	owner    *owner         // 创建（或复制）该节点的树的所有权标记，见 Clone
+	ext      *nodeExt[K, V] // 只有部分树才用到的状态，其他节点为 nil
+}
+
+// nodeExt 保存只有部分树才用到的节点状态，需要时才分配，普通 BTree 的节点不为它付出内存。
+type nodeExt[K any, V any] struct {
+	// dead[i] 为 true 表示 items[i] 已被惰性删除（墓碑），墓碑仍占据树中的位置，直到 Compact 把它物理删除。
+	// 只在 LazyDelete 模式下、节点中第一次出现墓碑时分配，之后与 items 一一对应（见 lazy.go）。
+	dead []bool
+	// agg 是子树中所有存活 value 的聚合值，只在 NewWithMonoid 创建的树中维护（见 augment.go）
+	agg V
+	// buffer 是尚未下推的消息，按 key 有序，只在 BufferedBTree 的内部节点中出现（见 buffered.go）
+	buffer []message[K, V]
 }
 
-func newLeafNode[K any, V any]() *node[K, V] {
-	return &node[K, V]{
-		isLeaf: true,
-		// items:    make([]item[K, V], 0),
-		// children: nil,
+// owner 是树对节点的所有权标记：只有 owner 与树相同的节点才能原地修改。
+// 不能是空结构体，指向零大小对象的指针可能彼此相等。
+type owner struct{ _ byte }
+
+// newNode 创建一个一次性预留满容量的节点：items 容量为 capacity，内部节点的 children 容量为 capacity+1。
+// 之后节点内的插入、删除和移位都在原数组上进行，不会因 append 扩容而重新分配。
+// withExt 为 true 时同时创建 ext，两者在同一次分配中。
+func newNode[K any, V any](isLeaf bool, capacity int, o *owner, withExt bool) *node[K, V] {
+	var n *node[K, V]
+	if withExt {
+		p := new(struct {
+			node[K, V]
+			ext nodeExt[K, V]
+		})
+		n = &p.node
+		n.ext = &p.ext
+	} else {
+		n = new(node[K, V])
+	}
+	n.isLeaf = isLeaf
+	n.items = make([]item[K, V], 0, capacity)
+	n.owner = o
+	if !isLeaf {
+		n.children = make([]*node[K, V], 0, capacity+1)
 	}
+	return n
 }
 
-func newInternalNodeWithChild[K any, V any](child *node[K, V]) *node[K, V] {
-	return &node[K, V]{
-		isLeaf:   false,
-		items:    make([]item[K, V], 0),
-		children: []*node[K, V]{child},
+// extension 返回 n.ext，没有时分配
+func (n *node[K, V]) extension() *nodeExt[K, V] {
+	if n.ext == nil {
+		n.ext = &nodeExt[K, V]{}
 	}
+	return n.ext
 }
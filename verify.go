@@ -9,7 +9,47 @@ func (t *BTree[K, V]) Verify() error {
 		return nil
 	}
 	leafDepth := -1
-	return t.verifyNode(t.root, true, nil, nil, 0, &leafDepth)
+	if err := t.verifyNode(t.root, true, nil, nil, 0, &leafDepth); err != nil {
+		return err
+	}
+	if t.monoid != nil {
+		if err := t.verifyAggregates(t.root, 0); err != nil {
+			return err
+		}
+	}
+	return t.verifyCounts()
+}
+
+// verifyCounts 检查 size 和 tombstones 是否与树中实际的存活元素、墓碑个数一致。
+func (t *BTree[K, V]) verifyCounts() error {
+	live, dead := t.countItems(t.root)
+	if dead > 0 && !t.options.LazyDelete {
+		return fmt.Errorf("btree: found %d tombstones but LazyDelete is disabled", dead)
+	}
+	if live != t.size {
+		return fmt.Errorf("btree: size is %d but tree holds %d live items", t.size, live)
+	}
+	if dead != t.tombstones {
+		return fmt.Errorf("btree: tombstones is %d but tree holds %d tombstones", t.tombstones, dead)
+	}
+	return nil
+}
+
+// countItems 统计以 n 为根的子树中存活元素和墓碑的个数。
+func (t *BTree[K, V]) countItems(n *node[K, V]) (live, dead int) {
+	for i := range n.items {
+		if n.isDead(i) {
+			dead++
+		} else {
+			live++
+		}
+	}
+	for _, child := range n.children {
+		l, d := t.countItems(child)
+		live += l
+		dead += d
+	}
+	return live, dead
 }
 
 // verifyNode 递归检查以 n 为根的子树是否满足 B-Tree 不变式。
@@ -23,31 +63,40 @@ func (t *BTree[K, V]) verifyNode(n *node[K, V], isRoot bool, minKey *K, maxKey *
 		return fmt.Errorf("btree: encountered nil node at depth %d", depth)
 	}
 
-	degree := t.options.Degree
 	itemCount := len(n.items)
-	// 1. 检查 key 数量范围
+	// 1. 检查 key 数量范围，上下限取决于策略（BStar 的下限为 2/3 满）
+	maxItems := t.maxItems(isRoot)
 	if isRoot {
-		if itemCount > 2*degree-1 {
-			return fmt.Errorf("btree: root has %d keys, max allowed %d", itemCount, 2*degree-1)
+		if itemCount > maxItems {
+			return fmt.Errorf("btree: root has %d keys, max allowed %d", itemCount, maxItems)
 		}
 	} else { // 非根节点
-		if itemCount < degree-1 || itemCount > 2*degree-1 {
-			return fmt.Errorf("btree: non-root node at depth %d has %d keys, expect in [%d,%d]", depth, itemCount, degree-1, 2*degree-1)
+		minItems := t.minItems()
+		if t.looseRight && maxKey == nil {
+			// 没有右边界的节点就是最右路径上的节点，连续追加时的 90/10 分裂让它可以很空
+			minItems = 1
+		}
+		if itemCount < minItems || itemCount > maxItems {
+			return fmt.Errorf("btree: non-root node at depth %d has %d keys, expect in [%d,%d]", depth, itemCount, minItems, maxItems)
 		}
 	}
 
+	if dead := n.deadFlags(); dead != nil && len(dead) != itemCount {
+		return fmt.Errorf("btree: node at depth %d has %d keys but %d tombstone flags", depth, itemCount, len(dead))
+	}
+
 	// 2. 检查 keys 有序 且在 (minKey, maxKey) 范围内
 	for i := range itemCount {
 		key := n.items[i].key
-		if minKey != nil && t.lessThan(key, *minKey) {
+		if minKey != nil && !t.greaterThan(key, *minKey) {
 			return fmt.Errorf("btree: node at depth %d has key %v <= minKey %v", depth, key, *minKey)
 		}
-		if maxKey != nil && t.greaterThan(key, *maxKey) {
+		if maxKey != nil && !t.lessThan(key, *maxKey) {
 			return fmt.Errorf("btree: node at depth %d has key %v >= maxKey %v", depth, key, *maxKey)
 		}
 		// 检查有序性 从第二个 key 开始检查
-		if i > 0 && t.greaterThan(n.items[i-1].key, key) {
-			return fmt.Errorf("btree: node at depth %d has unordered keys: %v > %v", depth, n.items[i-1].key, key)
+		if i > 0 && !t.lessThan(n.items[i-1].key, key) {
+			return fmt.Errorf("btree: node at depth %d has unordered keys: %v >= %v", depth, n.items[i-1].key, key)
 		}
 	}
 
@@ -58,3 +58,12 @@ func TestVerify_DetectBadChildrenCount(t *testing.T) {
 		t.Fatalf("expected Verify() to fail on bad children count, got nil")
 	}
 }
+
+// 节点内出现重复 key，Verify 必须报错
+func TestVerify_DetectDuplicateKeys(t *testing.T) {
+	tree := treeFromRoot(internalNode([]int{10}, leaf(5, 10), leaf(20)), 2)
+
+	if err := tree.Verify(); err == nil {
+		t.Fatalf("expected Verify() to fail on duplicate keys, got nil")
+	}
+}